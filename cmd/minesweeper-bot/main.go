@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/minesweeper/pkg/client"
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/service"
+	"github.com/minesweeper/pkg/solver"
+)
+
+//result is the outcome of a single game
+type result struct {
+	won     bool
+	guesses int
+	clicks  int
+	err     error
+}
+
+func main() {
+	var (
+		addr        = flag.String("addr", "", "Address of the service to play against. If empty, the bot plays against a service in its own process")
+		games       = flag.Int("games", 1000, "How many games to play")
+		concurrency = flag.Int("concurrency", 8, "How many games are played at the same time")
+		name        = flag.String("preset", "beginner", "Board to play: beginner, intermediate or expert")
+		rows        = flag.Int("rows", 0, "Rows of a custom board, overrides the preset")
+		columns     = flag.Int("columns", 0, "Columns of a custom board, overrides the preset")
+		mines       = flag.Int("mines", 0, "Mines of a custom board, overrides the preset")
+	)
+	flag.Parse()
+
+	board, ok := models.PresetOf(*name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown preset %q\n", *name)
+		os.Exit(2)
+	}
+	if *rows > 0 {
+		board.Rows = *rows
+	}
+	if *columns > 0 {
+		board.Columns = *columns
+	}
+	if *mines > 0 {
+		board.Mines = *mines
+	}
+
+	var svc service.Minesweepersvc
+	if *addr != "" {
+		var err error
+		if svc, err = client.New(*addr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	} else {
+		svc = service.New(log.NewNopLogger())
+	}
+
+	//game names must not clash with earlier runs against the same service
+	run := time.Now().UnixNano()
+	jobs := make(chan int)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < *concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results <- play(svc, fmt.Sprintf("bot-%d-%d", run, i), board)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < *games; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	var played, won, guesses, clicks, failed int
+	for r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Fprintln(os.Stderr, r.err)
+			continue
+		}
+		played++
+		guesses += r.guesses
+		clicks += r.clicks
+		if r.won {
+			won++
+		}
+	}
+	elapsed := time.Since(start)
+
+	fmt.Printf("board:       %dx%d, %d mines\n", board.Rows, board.Columns, board.Mines)
+	fmt.Printf("games:       %d (%d failed)\n", played, failed)
+	if played > 0 {
+		fmt.Printf("win rate:    %.2f%%\n", 100*float64(won)/float64(played))
+		fmt.Printf("guesses:     %.2f per game\n", float64(guesses)/float64(played))
+	}
+	fmt.Printf("throughput:  %.1f games/s, %.1f clicks/s\n", float64(played)/elapsed.Seconds(), float64(clicks)/elapsed.Seconds())
+	fmt.Printf("elapsed:     %v\n", elapsed)
+}
+
+//play plays a whole game: it clicks every cell the solver proves safe, and guesses the cell least likely to be a mine when nothing is certain
+func play(svc service.Minesweepersvc, name string, board models.Preset) result {
+
+	ctx := context.Background()
+	game := &models.Game{Name: name, Rows: board.Rows, Columns: board.Columns, Mines: board.Mines}
+	if err := svc.NewGame(ctx, game); err != nil {
+		return result{err: err}
+	}
+
+	var r result
+	for !game.Status.Over() {
+		view := solver.FromGame(game)
+		deductions := solver.Solve(view)
+
+		var moves []solver.Cell
+		for _, d := range deductions {
+			if !d.Mine {
+				moves = append(moves, d.Cell)
+			}
+		}
+		if len(moves) == 0 {
+			cell, _ := solver.Guess(view)
+			//no cell is left to guess when no placement of the mines agrees with the numbers
+			if cell.Row < 0 {
+				return result{err: fmt.Errorf("game %s: no cell left to guess", name)}
+			}
+			moves = append(moves, cell)
+			r.guesses++
+		}
+
+		for _, m := range moves {
+			//a click may reveal the cells of the next ones
+			if game.Board[m.Row][m.Column].Clicked || game.Status.Over() {
+				continue
+			}
+			next, err := svc.Click(ctx, models.ClickRequest{Name: name, Row: m.Row, Column: m.Column})
+			if err != nil {
+				return result{err: err}
+			}
+			game = next
+			r.clicks++
+		}
+	}
+	r.won = game.Status == models.StatusWon
+	return r
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const usage = `Moves:
+  r c        click the cell in row r, column c
+  f r c      flag or unflag the cell. Cells that hold several mines take a flag for each, and lose them all after the last one
+  chord r c  click every unflagged neighbour of a number with all its flags set
+  pause      stop the game, clock included. Its board is hidden until it's resumed
+  resume     go on with a paused game
+  help       show this help
+  quit       leave the game (it stays saved in the service)
+On cube boards every cell starts with its layer: l r c, f l r c and chord l r c`
+
+const pausedMessage = "the game is paused, type resume to go on with it"
+
+func main() {
+	var (
+		addr    = flag.String("addr", "http://localhost:8080", "Address of the minesweeper service")
+		name    = flag.String("name", "", "Name of the game to play")
+		create  = flag.Bool("new", false, "Create the game instead of loading it")
+		rows    = flag.Int("rows", 0, "Rows of a new game (service default if 0)")
+		columns = flag.Int("columns", 0, "Columns of a new game (service default if 0)")
+		mines   = flag.Int("mines", 0, "Mines of a new game (service default if 0)")
+		lives   = flag.Int("lives", 0, "Mines a new game lets the player hit before it's over (1 if 0)")
+		shape   = flag.String("topology", "", "Shape of the board of a new game: plane, torus, hex or cube (plane if empty)")
+		layers  = flag.Int("layers", 0, "Layers of a new cube board (service default if 0)")
+		ascii   = flag.Bool("ascii", false, "Draw the board with plain ASCII and no colours, for dumb terminals")
+	)
+	flag.Parse()
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "a game -name is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c := client{base: strings.TrimRight(*addr, "/"), http: &http.Client{Timeout: 10 * time.Second}}
+	if *create {
+		game := models.Game{Name: *name, Rows: *rows, Columns: *columns, Mines: *mines, Lives: *lives, Topology: *shape, Layers: *layers}
+		bailOnError(c.newGame(&game))
+	}
+	game, err := c.loadGame(*name)
+	bailOnError(err)
+
+	p := player{
+		client: c,
+		game:   game,
+		screen: newScreen(*ascii),
+	}
+	//the clock starts with the first click, which may have happened in an earlier session
+	if replay, err := c.replay(*name); err == nil {
+		p.start, p.end = clickTimes(replay)
+	}
+
+	fmt.Println(usage)
+	p.play(bufio.NewScanner(os.Stdin))
+}
+
+//player keeps the state of the session: the game as the service last returned it, flags included
+type player struct {
+	client client
+	game   *models.Game
+	screen screen
+	start  time.Time //time of the first click
+	end    time.Time //time of the last click, once the game is over
+}
+
+func (p *player) play(in *bufio.Scanner) {
+
+	p.draw("")
+	for !p.over() {
+		fmt.Print("> ")
+		if !in.Scan() {
+			return
+		}
+		fields := strings.Fields(in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var msg string
+		switch fields[0] {
+		case "q", "quit", "exit":
+			return
+		case "h", "help":
+			fmt.Println(usage)
+			continue
+		case "pause", "resume":
+			msg = p.pause(fields[0])
+		case "f", "flag":
+			row, column, err := p.cell(fields[1:])
+			if err != nil {
+				msg = err.Error()
+				break
+			}
+			msg = p.flag(row, column)
+		case "c", "chord":
+			row, column, err := p.cell(fields[1:])
+			if err != nil {
+				msg = err.Error()
+				break
+			}
+			msg = p.chord(row, column)
+		default:
+			row, column, err := p.cell(fields)
+			if err != nil {
+				msg = err.Error()
+				break
+			}
+			msg = p.click(row, column)
+		}
+		p.draw(msg)
+	}
+}
+
+func (p *player) over() bool {
+	return p.game.Status.Over()
+}
+
+//paused tells whether the game is paused, in which case the service sends it without its board
+func (p *player) paused() bool {
+	return p.game.Status == models.StatusPaused || len(p.game.Board) == 0
+}
+
+//pause pauses or resumes the game, as the action says
+func (p *player) pause(action string) string {
+
+	game, err := p.client.pause(p.game.Name, action)
+	if err != nil {
+		return err.Error()
+	}
+	p.game = game
+	return ""
+}
+
+//cell parses the row and column of a move. On cube boards the layer comes first, and the row returned is that of the whole board
+func (p *player) cell(fields []string) (int, int, error) {
+
+	layer := 0
+	if p.game.Layers > 1 {
+		if len(fields) != 3 {
+			return 0, 0, errors.New("expected a layer, a row and a column, type help for the moves")
+		}
+		var err error
+		layer, err = strconv.Atoi(fields[0])
+		if err != nil || layer < 0 || layer >= p.game.Layers {
+			return 0, 0, fmt.Errorf("layer must be between 0 and %d", p.game.Layers-1)
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 2 {
+		return 0, 0, errors.New("expected a row and a column, type help for the moves")
+	}
+	row, err := strconv.Atoi(fields[0])
+	if err != nil || row < 0 || row >= p.game.Rows {
+		return 0, 0, fmt.Errorf("row must be between 0 and %d", p.game.Rows-1)
+	}
+	column, err := strconv.Atoi(fields[1])
+	if err != nil || column < 0 || column >= p.game.Columns {
+		return 0, 0, fmt.Errorf("column must be between 0 and %d", p.game.Columns-1)
+	}
+	return layer*p.game.Rows + row, column, nil
+}
+
+func (p *player) click(row, column int) string {
+
+	if p.paused() {
+		return pausedMessage
+	}
+	if cell := p.game.Board[row][column]; cell.Flag && !cell.Clicked {
+		return "that cell is flagged, unflag it first"
+	}
+	game, err := p.client.click(p.request(row, column))
+	if err != nil {
+		return err.Error()
+	}
+	now := time.Now()
+	if p.start.IsZero() {
+		p.start = now
+	}
+	p.game = game
+	if p.over() {
+		p.end = now
+	}
+	return ""
+}
+
+func (p *player) flag(row, column int) string {
+
+	if p.paused() {
+		return pausedMessage
+	}
+	if p.game.Board[row][column].Clicked {
+		return "only hidden cells can be flagged"
+	}
+	game, err := p.client.flag(p.request(row, column))
+	if err != nil {
+		return err.Error()
+	}
+	p.game = game
+	return ""
+}
+
+//request is the move on a cell, found in its layer on cube boards
+func (p *player) request(row, column int) models.ClickRequest {
+
+	req := models.ClickRequest{Name: p.game.Name, Row: row, Column: column}
+	if p.game.Layers > 1 {
+		req.Layer, req.Row = row/p.game.Rows, row%p.game.Rows
+	}
+	return req
+}
+
+//marked is how many mines of a cell the player knows of: those of a mine hit, or the flags put in a hidden cell.
+//Cells of games with several mines per cell take a flag for each of their mines
+func marked(cell models.Cell) int {
+
+	switch {
+	case cell.Clicked && cell.Mines > 0:
+		return cell.Mines
+	case cell.Clicked && cell.Mine:
+		return 1
+	case cell.Clicked:
+		return 0
+	case cell.Flags > 0:
+		return cell.Flags
+	case cell.Flag:
+		return 1
+	}
+	return 0
+}
+
+//chord clicks all the hidden neighbours of a number once they hold as many flags, and mines hit, as the number says.
+//The service takes the chord as a single move
+func (p *player) chord(row, column int) string {
+
+	if p.paused() {
+		return pausedMessage
+	}
+	if cell := p.game.Board[row][column]; !cell.Clicked || cell.Number == 0 {
+		return "only clicked numbers can be chorded"
+	}
+	game, err := p.client.chord(p.request(row, column))
+	if err != nil {
+		return err.Error()
+	}
+	p.game = game
+	if p.over() {
+		p.end = time.Now()
+	}
+	return ""
+}
+
+func (p *player) draw(msg string) {
+
+	var elapsed time.Duration
+	switch {
+	case !p.end.IsZero():
+		elapsed = p.end.Sub(p.start)
+	case !p.start.IsZero():
+		elapsed = time.Since(p.start)
+	}
+	//undos of practice games add to the time
+	elapsed += time.Duration(p.game.Penalty) * time.Second
+	//mines already hit are flagged by the service
+	mines := p.game.Mines
+	for _, row := range p.game.Board {
+		for _, cell := range row {
+			mines -= marked(cell)
+		}
+	}
+	fmt.Print(p.screen.board(p.game))
+	fmt.Printf("Mines: %d   Lives: %d   Time: %ds\n", mines, p.game.Lives, int(elapsed.Seconds()))
+	switch p.game.Status {
+	case models.StatusLost:
+		fmt.Println("BOOM! Game over.")
+	case models.StatusWon:
+		fmt.Println("Victory!")
+	case models.StatusTimedOut:
+		fmt.Println("Time is up! Game over.")
+	case models.StatusPaused:
+		fmt.Println("Paused. " + pausedMessage)
+	}
+	if msg != "" {
+		fmt.Println(msg)
+	}
+}
+
+//clickTimes returns the time of the first click of a replay and, if the game is over, the time of the last one
+func clickTimes(replay *models.Replay) (start, end time.Time) {
+
+	for _, event := range replay.Events {
+		if event.Action != models.ActionClick && event.Action != models.ActionChord {
+			continue
+		}
+		if start.IsZero() {
+			start = event.Time
+		}
+		end = event.Time
+	}
+	if !replay.Status.Over() {
+		end = time.Time{}
+	}
+	return start, end
+}
+
+//client makes the calls to the HTTP API of the service
+type client struct {
+	base string
+	http *http.Client
+}
+
+func (c client) newGame(game *models.Game) error {
+	return c.do(http.MethodPost, "/minesweeper/games", game, nil)
+}
+
+func (c client) loadGame(name string) (*models.Game, error) {
+	var game models.Game
+	err := c.do(http.MethodGet, "/minesweeper/games/"+url.PathEscape(name), nil, &game)
+	return &game, err
+}
+
+func (c client) click(req models.ClickRequest) (*models.Game, error) {
+	var game models.Game
+	err := c.do(http.MethodPut, "/minesweeper/games", req, &game)
+	return &game, err
+}
+
+//pause sends the pause or the resume of a game, as the action says
+func (c client) pause(name string, action string) (*models.Game, error) {
+	var game models.Game
+	err := c.do(http.MethodPost, "/minesweeper/games/"+url.PathEscape(name)+"/"+action, nil, &game)
+	return &game, err
+}
+
+func (c client) flag(req models.ClickRequest) (*models.Game, error) {
+	var game models.Game
+	err := c.do(http.MethodPut, "/minesweeper/games/"+url.PathEscape(req.Name)+"/flag", req, &game)
+	return &game, err
+}
+
+func (c client) chord(req models.ClickRequest) (*models.Game, error) {
+	var game models.Game
+	err := c.do(http.MethodPut, "/minesweeper/games/"+url.PathEscape(req.Name)+"/chord", req, &game)
+	return &game, err
+}
+
+func (c client) replay(name string) (*models.Replay, error) {
+	var replay models.Replay
+	err := c.do(http.MethodGet, "/minesweeper/games/"+url.PathEscape(name)+"/replay", nil, &replay)
+	return &replay, err
+}
+
+//do sends the request body as JSON and decodes the JSON response into res, if any.
+//The service answers errors with their message as the body
+func (c client) do(method, path string, body, res interface{}) error {
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, c.base+path, &buf)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.New(strings.TrimSpace(string(msg)))
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+func bailOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
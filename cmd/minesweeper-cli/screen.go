@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
+)
+
+//ANSI colours of each number, the same the classic game uses as far as terminals allow
+var numberColours = [9]string{"", "34", "32", "31", "35", "33", "36", "37", "90"}
+
+//screen draws boards, with unicode symbols and colours or with plain ASCII
+type screen struct {
+	ascii  bool
+	hidden string
+	flag   string
+	mine   string
+	empty  string
+}
+
+func newScreen(ascii bool) screen {
+
+	if ascii {
+		return screen{ascii: true, hidden: "#", flag: "F", mine: "*", empty: "."}
+	}
+	return screen{hidden: "■", flag: "\x1b[31m⚑\x1b[0m", mine: "\x1b[1m✹\x1b[0m", empty: "·"}
+}
+
+//board draws the board with row and column numbers around it. Mines are only shown once the game is over,
+//except the ones hit in games with lives, which are flagged. Hexagonal boards shift their odd rows half a cell to the right,
+//and cube boards are drawn a layer after another
+func (s screen) board(game *models.Game) string {
+
+	over := game.Status.Over()
+	var sb strings.Builder
+
+	//every cell is as wide as the largest number the board can have, so columns stay in line. Hexagonal cells
+	//take a character more when needed for half a cell to be a whole number of characters
+	digits := len(fmt.Sprint(largest(game)))
+	width := digits + 2
+	hexagons := topology.Of(game.Topology).Geometry().Cell == "hexagon"
+	if hexagons && width%2 == 1 {
+		width++
+	}
+	pad := strings.Repeat(" ", width-digits-1)
+	align := strings.Repeat(" ", digits-1)
+
+	sb.WriteString("    ")
+	for j := 0; j < game.Columns; j++ {
+		fmt.Fprintf(&sb, "%*d ", width-1, j)
+	}
+	sb.WriteString("\n")
+	for i, row := range game.Board {
+		number := i
+		if game.Layers > 1 {
+			number = i % game.Rows
+			if number == 0 {
+				fmt.Fprintf(&sb, "layer %d\n", i/game.Rows)
+			}
+		}
+		fmt.Fprintf(&sb, "%3d ", number)
+		if hexagons && i%2 == 1 {
+			sb.WriteString(strings.Repeat(" ", width/2))
+		}
+		for _, cell := range row {
+			sb.WriteString(pad)
+			switch {
+			case over && cell.Mine:
+				sb.WriteString(align + s.mine)
+			//cells revealed around an empty one keep the flags put in them, which were wrong
+			case cell.Flag && (!cell.Clicked || cell.Mine):
+				sb.WriteString(align + s.flag)
+			case !cell.Clicked:
+				sb.WriteString(align + s.hidden)
+			case cell.Number == 0:
+				sb.WriteString(align + s.empty)
+			case s.ascii:
+				fmt.Fprintf(&sb, "%*d", digits, cell.Number)
+			default:
+				//boards with cubes or several mines per cell have numbers beyond 8, which take the colour of 8
+				colour := numberColours[len(numberColours)-1]
+				if cell.Number < len(numberColours) {
+					colour = numberColours[cell.Number]
+				}
+				fmt.Fprintf(&sb, "\x1b[1;%sm%*d\x1b[0m", colour, digits, cell.Number)
+			}
+			sb.WriteString(" ")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+//largest returns the largest number a cell of the board can show: every neighbour holding as many mines as a cell can
+func largest(game *models.Game) int {
+
+	most := game.MaxMines
+	if most < 1 {
+		most = 1
+	}
+	largest := most * topology.For(game.Topology, game.Layers, game.Offsets).Geometry().Neighbours
+	if game.Mines < largest {
+		largest = game.Mines
+	}
+	return largest
+}
@@ -0,0 +1,533 @@
+//Package client implements the Minesweepersvc interface over the HTTP API of the service,
+//so other Go programs can use the service the same way whether it runs in their process or remotely.
+//It is built on go-kit's http client, with encoders and decoders that mirror the ones in pkg/http
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/sd"
+	"github.com/go-kit/kit/sd/lb"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/minesweeper/pkg/endpoints"
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/service"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	defaultRetries = 3
+)
+
+//ErrNotSupported is returned by the operations of the service the HTTP API doesn't expose
+var ErrNotSupported = errors.New("operation not supported over HTTP")
+
+//Error is an error returned by the service itself, as opposed to a failure reaching it. Only the latter are retried
+type Error struct {
+	StatusCode int    //HTTP status of the response
+	Message    string //Message the service answered with
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+//config holds the settings Options change
+type config struct {
+	timeout    time.Duration
+	retries    int
+	httpClient *http.Client
+	before     []httptransport.RequestFunc
+}
+
+//Option changes the settings of the client
+type Option func(*config)
+
+//Timeout sets how long each call can take, retries included
+func Timeout(timeout time.Duration) Option {
+	return func(c *config) { c.timeout = timeout }
+}
+
+//Retries sets how many times a call is attempted when the service can't be reached. 1 means no retries.
+//Only reads are retried: a move or a new game may have been made even though its answer was lost
+func Retries(retries int) Option {
+	return func(c *config) { c.retries = retries }
+}
+
+//Header adds a header to every request, such as the Authorization header
+func Header(key, value string) Option {
+	return func(c *config) { c.before = append(c.before, httptransport.SetRequestHeader(key, value)) }
+}
+
+//HTTPClient sets the http.Client requests are sent with
+func HTTPClient(client *http.Client) Option {
+	return func(c *config) { c.httpClient = client }
+}
+
+//minesweeper implements Minesweepersvc calling the endpoints of a remote service
+type minesweeper struct {
+	endpoints.Endpoints
+}
+
+//New returns a Minesweepersvc that calls the service listening at instance, such as "http://localhost:8080"
+func New(instance string, options ...Option) (service.Minesweepersvc, error) {
+
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	base, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+	base.Path = strings.TrimRight(base.Path, "/")
+
+	cfg := config{
+		timeout:    defaultTimeout,
+		retries:    defaultRetries,
+		httpClient: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	if cfg.retries < 1 {
+		return nil, errors.New("at least one attempt is needed")
+	}
+	//moves on shared games carry the token of the player in their context, as they do in process
+	cfg.before = append(cfg.before, tokenFromContext)
+
+	//newEndpoint builds the endpoint of one method, retrying it when the service can't be reached if the method is a read.
+	//Sending anything else again could make a move twice, such as a flag put and then taken back
+	newEndpoint := func(method string, enc httptransport.EncodeRequestFunc, dec httptransport.DecodeResponseFunc) endpoint.Endpoint {
+		e := httptransport.NewClient(method, base, enc, dec,
+			httptransport.SetClient(cfg.httpClient),
+			httptransport.ClientBefore(cfg.before...),
+		).Endpoint()
+		balancer := lb.NewRoundRobin(sd.FixedEndpointer{e})
+		attempts := cfg.retries
+		if method != http.MethodGet {
+			attempts = 1
+		}
+		return lb.Retry(attempts, cfg.timeout, balancer)
+	}
+
+	return minesweeper{endpoints.Endpoints{
+		GetMinesweeperEndpoint: newEndpoint(http.MethodGet, encodeGetMinesweeperRequest, decodeGetMinesweeperResponse),
+		NewGameEndpoint:        newEndpoint(http.MethodPost, encodeNewGameRequest, decodeNewGameResponse),
+		LoadGameEndpoint:       newEndpoint(http.MethodGet, encodeLoadGameRequest, decodeLoadGameResponse),
+		ListGamesEndpoint:      newEndpoint(http.MethodGet, encodeListGamesRequest, decodeListGamesResponse),
+		DeleteGameEndpoint:     newEndpoint(http.MethodDelete, encodeDeleteGameRequest, decodeDeleteGameResponse),
+		ClickEndpoint:          newEndpoint(http.MethodPut, encodeClickRequest, decodeClickResponse),
+		FlagEndpoint:           newEndpoint(http.MethodPut, encodeFlagRequest, decodeFlagResponse),
+		ChordEndpoint:          newEndpoint(http.MethodPut, encodeChordRequest, decodeChordResponse),
+		ReplayEndpoint:         newEndpoint(http.MethodGet, encodeReplayRequest, decodeReplayResponse),
+		ExportLayoutEndpoint:   newEndpoint(http.MethodGet, encodeExportLayoutRequest, decodeExportLayoutResponse),
+		HintEndpoint:           newEndpoint(http.MethodGet, encodeHintRequest, decodeHintResponse),
+		UndoEndpoint:           newEndpoint(http.MethodPost, encodeUndoRequest, decodeUndoResponse),
+		CreateMatchEndpoint:    newEndpoint(http.MethodPost, encodeCreateMatchRequest, decodeCreateMatchResponse),
+		JoinMatchEndpoint:      newEndpoint(http.MethodPost, encodeJoinMatchRequest, decodeJoinMatchResponse),
+		StartMatchEndpoint:     newEndpoint(http.MethodPost, encodeStartMatchRequest, decodeStartMatchResponse),
+		StandingsEndpoint:      newEndpoint(http.MethodGet, encodeStandingsRequest, decodeStandingsResponse),
+		JoinGameEndpoint:       newEndpoint(http.MethodPost, encodeJoinGameRequest, decodeJoinGameResponse),
+		ScoresEndpoint:         newEndpoint(http.MethodGet, encodeScoresRequest, decodeScoresResponse),
+		RatingEndpoint:         newEndpoint(http.MethodGet, encodeRatingRequest, decodeRatingResponse),
+		LadderEndpoint:         newEndpoint(http.MethodGet, encodeLadderRequest, decodeLadderResponse),
+		QueueEndpoint:          newEndpoint(http.MethodPost, encodeQueueRequest, decodeQueueResponse),
+		TicketEndpoint:         newEndpoint(http.MethodGet, encodeTicketRequest, decodeTicketResponse),
+		DailyEndpoint:          newEndpoint(http.MethodGet, encodeDailyRequest, decodeDailyResponse),
+		LeaderboardEndpoint:    newEndpoint(http.MethodGet, encodeLeaderboardRequest, decodeLeaderboardResponse),
+		LayerEndpoint:          newEndpoint(http.MethodGet, encodeLayerRequest, decodeLayerResponse),
+		PauseEndpoint:          newEndpoint(http.MethodPost, encodePauseRequest, decodePauseResponse),
+		ResumeEndpoint:         newEndpoint(http.MethodPost, encodeResumeRequest, decodeResumeResponse),
+		SurrenderEndpoint:      newEndpoint(http.MethodPost, encodeSurrenderRequest, decodeSurrenderResponse),
+		RestartEndpoint:        newEndpoint(http.MethodPost, encodeRestartRequest, decodeRestartResponse),
+		RetryEndpoint:          newEndpoint(http.MethodPost, encodeRetryRequest, decodeRetryResponse),
+		//updates are streamed for as long as the caller wants them, so they aren't retried nor timed out
+		SubscribeEndpoint: httptransport.NewClient(http.MethodGet, base, encodeSubscribeRequest, decodeSubscribeResponse,
+			httptransport.SetClient(cfg.httpClient),
+			httptransport.ClientBefore(cfg.before...),
+			httptransport.BufferedStream(true),
+		).Endpoint(),
+	}}, nil
+}
+
+//GetMinesweeper implements Minesweepersvc
+func (m minesweeper) GetMinesweeper(ctx context.Context) (res service.MinesweeperResponse, err error) {
+
+	response, err := m.GetMinesweeperEndpoint(ctx, endpoints.GetMinesweeperRequest{})
+	if err != nil {
+		return service.MinesweeperResponse{}, unwrap(err)
+	}
+	r := response.(endpoints.GetMinesweeperResponse)
+	return r.Res, r.Err
+}
+
+//NewGame implements Minesweepersvc. As the service does in process, the game is filled in with its board and defaults,
+//which takes loading it after it's created
+func (m minesweeper) NewGame(ctx context.Context, game *models.Game) (err error) {
+
+	response, err := m.NewGameEndpoint(ctx, endpoints.NewGameRequest{Req: game})
+	if err != nil {
+		return unwrap(err)
+	}
+	if r := response.(endpoints.NewGameResponse); r.Err != nil {
+		return r.Err
+	}
+	created, err := m.LoadGame(ctx, game.Name)
+	if err != nil {
+		return err
+	}
+	*game = *created
+	return nil
+}
+
+//LoadGame implements Minesweepersvc
+func (m minesweeper) LoadGame(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.LoadGameEndpoint(ctx, endpoints.LoadGameRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.LoadGameResponse)
+	return r.Res, r.Err
+}
+
+//SaveGame implements Minesweepersvc. The HTTP API doesn't expose it, games are only changed through their moves
+func (m minesweeper) SaveGame(ctx context.Context, game *models.Game) (err error) {
+	return ErrNotSupported
+}
+
+//ListGames implements Minesweepersvc
+func (m minesweeper) ListGames(ctx context.Context, query models.GameQuery) (res *models.GamePage, err error) {
+
+	response, err := m.ListGamesEndpoint(ctx, endpoints.ListGamesRequest{Req: query})
+	if err != nil {
+		return &models.GamePage{}, unwrap(err)
+	}
+	r := response.(endpoints.ListGamesResponse)
+	return r.Res, r.Err
+}
+
+//DeleteGame implements Minesweepersvc
+func (m minesweeper) DeleteGame(ctx context.Context, req models.DeleteGameRequest) (err error) {
+
+	response, err := m.DeleteGameEndpoint(ctx, endpoints.DeleteGameRequest{Req: req})
+	if err != nil {
+		return unwrap(err)
+	}
+	return response.(endpoints.DeleteGameResponse).Err
+}
+
+//Click implements Minesweepersvc
+func (m minesweeper) Click(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	response, err := m.ClickEndpoint(ctx, endpoints.ClickRequest{Req: req})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.ClickResponse)
+	return r.Res, r.Err
+}
+
+//Flag implements Minesweepersvc
+func (m minesweeper) Flag(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	response, err := m.FlagEndpoint(ctx, endpoints.FlagRequest{Req: req})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.FlagResponse)
+	return r.Res, r.Err
+}
+
+//Chord implements Minesweepersvc
+func (m minesweeper) Chord(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	response, err := m.ChordEndpoint(ctx, endpoints.ChordRequest{Req: req})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.ChordResponse)
+	return r.Res, r.Err
+}
+
+//Replay implements Minesweepersvc
+func (m minesweeper) Replay(ctx context.Context, name string) (res *models.Replay, err error) {
+
+	response, err := m.ReplayEndpoint(ctx, endpoints.ReplayRequest{Req: name})
+	if err != nil {
+		return &models.Replay{}, unwrap(err)
+	}
+	r := response.(endpoints.ReplayResponse)
+	return r.Res, r.Err
+}
+
+//ExportLayout implements Minesweepersvc
+func (m minesweeper) ExportLayout(ctx context.Context, name string) (res models.Layout, err error) {
+
+	response, err := m.ExportLayoutEndpoint(ctx, endpoints.ExportLayoutRequest{Req: name})
+	if err != nil {
+		return "", unwrap(err)
+	}
+	r := response.(endpoints.ExportLayoutResponse)
+	return r.Res, r.Err
+}
+
+//Hint implements Minesweepersvc
+func (m minesweeper) Hint(ctx context.Context, name string) (res *models.Hint, err error) {
+
+	response, err := m.HintEndpoint(ctx, endpoints.HintRequest{Req: name})
+	if err != nil {
+		return &models.Hint{}, unwrap(err)
+	}
+	r := response.(endpoints.HintResponse)
+	return r.Res, r.Err
+}
+
+//Undo implements Minesweepersvc
+func (m minesweeper) Undo(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.UndoEndpoint(ctx, endpoints.UndoRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.UndoResponse)
+	return r.Res, r.Err
+}
+
+//CreateMatch implements Minesweepersvc. As the service does in process, the match is filled in with its defaults
+func (m minesweeper) CreateMatch(ctx context.Context, match *models.Match) (res *models.Match, err error) {
+
+	response, err := m.CreateMatchEndpoint(ctx, endpoints.CreateMatchRequest{Req: match})
+	if err != nil {
+		return &models.Match{}, unwrap(err)
+	}
+	r := response.(endpoints.CreateMatchResponse)
+	if r.Err != nil {
+		return r.Res, r.Err
+	}
+	*match = *r.Res
+	return r.Res, nil
+}
+
+//JoinMatch implements Minesweepersvc
+func (m minesweeper) JoinMatch(ctx context.Context, req models.JoinRequest) (res *models.Game, err error) {
+
+	response, err := m.JoinMatchEndpoint(ctx, endpoints.JoinMatchRequest{Req: req})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.JoinMatchResponse)
+	return r.Res, r.Err
+}
+
+//StartMatch implements Minesweepersvc
+func (m minesweeper) StartMatch(ctx context.Context, name string) (res *models.Match, err error) {
+
+	response, err := m.StartMatchEndpoint(ctx, endpoints.StartMatchRequest{Req: name})
+	if err != nil {
+		return &models.Match{}, unwrap(err)
+	}
+	r := response.(endpoints.StartMatchResponse)
+	return r.Res, r.Err
+}
+
+//Standings implements Minesweepersvc
+func (m minesweeper) Standings(ctx context.Context, name string) (res *models.Standings, err error) {
+
+	response, err := m.StandingsEndpoint(ctx, endpoints.StandingsRequest{Req: name})
+	if err != nil {
+		return &models.Standings{}, unwrap(err)
+	}
+	r := response.(endpoints.StandingsResponse)
+	return r.Res, r.Err
+}
+
+//JoinGame implements Minesweepersvc
+func (m minesweeper) JoinGame(ctx context.Context, req models.JoinGameRequest) (res *models.Session, err error) {
+
+	response, err := m.JoinGameEndpoint(ctx, endpoints.JoinGameRequest{Req: req})
+	if err != nil {
+		return &models.Session{}, unwrap(err)
+	}
+	r := response.(endpoints.JoinGameResponse)
+	return r.Res, r.Err
+}
+
+//Subscribe implements Minesweepersvc. Updates stop when ctx is done or the connection is lost,
+//so an http.Client with a Timeout cuts them short
+func (m minesweeper) Subscribe(ctx context.Context, name string) (res <-chan models.Update, err error) {
+
+	response, err := m.SubscribeEndpoint(ctx, endpoints.SubscribeRequest{Req: name})
+	if err != nil {
+		return nil, err
+	}
+	r := response.(endpoints.SubscribeResponse)
+	return r.Res, r.Err
+}
+
+//Scores implements Minesweepersvc
+func (m minesweeper) Scores(ctx context.Context, name string) (res *models.Scoreboard, err error) {
+
+	response, err := m.ScoresEndpoint(ctx, endpoints.ScoresRequest{Req: name})
+	if err != nil {
+		return &models.Scoreboard{}, unwrap(err)
+	}
+	r := response.(endpoints.ScoresResponse)
+	return r.Res, r.Err
+}
+
+//Rating implements Minesweepersvc
+func (m minesweeper) Rating(ctx context.Context, player string) (res *models.PlayerRating, err error) {
+
+	response, err := m.RatingEndpoint(ctx, endpoints.RatingRequest{Req: player})
+	if err != nil {
+		return &models.PlayerRating{}, unwrap(err)
+	}
+	r := response.(endpoints.RatingResponse)
+	return r.Res, r.Err
+}
+
+//Ladder implements Minesweepersvc
+func (m minesweeper) Ladder(ctx context.Context) (res []models.PlayerRating, err error) {
+
+	response, err := m.LadderEndpoint(ctx, endpoints.LadderRequest{})
+	if err != nil {
+		return nil, unwrap(err)
+	}
+	r := response.(endpoints.LadderResponse)
+	return r.Res, r.Err
+}
+
+//Queue implements Minesweepersvc
+func (m minesweeper) Queue(ctx context.Context, req models.QueueRequest) (res *models.Ticket, err error) {
+
+	response, err := m.QueueEndpoint(ctx, endpoints.QueueRequest{Req: req})
+	if err != nil {
+		return &models.Ticket{}, unwrap(err)
+	}
+	r := response.(endpoints.QueueResponse)
+	return r.Res, r.Err
+}
+
+//Ticket implements Minesweepersvc
+func (m minesweeper) Ticket(ctx context.Context, player string) (res *models.Ticket, err error) {
+
+	response, err := m.TicketEndpoint(ctx, endpoints.TicketRequest{Req: player})
+	if err != nil {
+		return &models.Ticket{}, unwrap(err)
+	}
+	r := response.(endpoints.TicketResponse)
+	return r.Res, r.Err
+}
+
+//Daily implements Minesweepersvc
+func (m minesweeper) Daily(ctx context.Context, player string) (res *models.Game, err error) {
+
+	response, err := m.DailyEndpoint(ctx, endpoints.DailyRequest{Req: player})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.DailyResponse)
+	return r.Res, r.Err
+}
+
+//DailyLeaderboard implements Minesweepersvc
+func (m minesweeper) DailyLeaderboard(ctx context.Context, date string) (res *models.Leaderboard, err error) {
+
+	response, err := m.LeaderboardEndpoint(ctx, endpoints.LeaderboardRequest{Req: date})
+	if err != nil {
+		return &models.Leaderboard{}, unwrap(err)
+	}
+	r := response.(endpoints.LeaderboardResponse)
+	return r.Res, r.Err
+}
+
+//Layer implements Minesweepersvc
+func (m minesweeper) Layer(ctx context.Context, req models.LayerRequest) (res *models.Slice, err error) {
+
+	response, err := m.LayerEndpoint(ctx, endpoints.LayerRequest{Req: req})
+	if err != nil {
+		return &models.Slice{}, unwrap(err)
+	}
+	r := response.(endpoints.LayerResponse)
+	return r.Res, r.Err
+}
+
+//Pause implements Minesweepersvc
+func (m minesweeper) Pause(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.PauseEndpoint(ctx, endpoints.PauseRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.PauseResponse)
+	return r.Res, r.Err
+}
+
+//Resume implements Minesweepersvc
+func (m minesweeper) Resume(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.ResumeEndpoint(ctx, endpoints.ResumeRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.ResumeResponse)
+	return r.Res, r.Err
+}
+
+//Surrender implements Minesweepersvc
+func (m minesweeper) Surrender(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.SurrenderEndpoint(ctx, endpoints.SurrenderRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.SurrenderResponse)
+	return r.Res, r.Err
+}
+
+//Restart implements Minesweepersvc
+func (m minesweeper) Restart(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.RestartEndpoint(ctx, endpoints.RestartRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.RestartResponse)
+	return r.Res, r.Err
+}
+
+//Retry implements Minesweepersvc
+func (m minesweeper) Retry(ctx context.Context, name string) (res *models.Game, err error) {
+
+	response, err := m.RetryEndpoint(ctx, endpoints.RetryRequest{Req: name})
+	if err != nil {
+		return &models.Game{}, unwrap(err)
+	}
+	r := response.(endpoints.RetryResponse)
+	return r.Res, r.Err
+}
+
+//tokenFromContext sends the token of the player in the context, if any, as the service expects it
+func tokenFromContext(ctx context.Context, r *http.Request) context.Context {
+
+	if token := service.TokenFrom(ctx); token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return ctx
+}
+
+//unwrap returns the error that ended the retries, which is the one callers care about
+func unwrap(err error) error {
+
+	if retry, ok := err.(lb.RetryError); ok && retry.Final != nil {
+		return retry.Final
+	}
+	return err
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/minesweeper/pkg/endpoints"
+	httpmine "github.com/minesweeper/pkg/http"
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/service"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+//newServer starts the whole service behind its HTTP handler, as cmd/minesweeper does
+func newServer() *httptest.Server {
+
+	logger := log.NewNopLogger()
+	svc := service.New(logger)
+	eps := endpoints.New(svc, logger)
+	return httptest.NewServer(httpmine.NewHTTPHandler(eps, logger))
+}
+
+func TestClient(t *testing.T) {
+
+	server := newServer()
+	defer server.Close()
+
+	svc, err := New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.TODO()
+
+	Convey("Test Client", t, func() {
+		Convey("New Game", func() {
+			game := models.Game{Name: "remote", Layout: "*..\n...\n..*"}
+			So(svc.NewGame(ctx, &game), ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusNew)
+			//mines, and the numbers of cells not clicked yet, stay with the service while the game is played
+			So(game.Board[0][0].Mine, ShouldBeFalse)
+			So(game.Board[1][1].Number, ShouldEqual, 0)
+		})
+		Convey("Existing Game", func() {
+			game := models.Game{Name: "remote"}
+			So(svc.NewGame(ctx, &game), ShouldNotBeNil)
+		})
+		Convey("Load Game", func() {
+			game, err := svc.LoadGame(ctx, "remote")
+			So(err, ShouldBeNil)
+			So(game.Mines, ShouldEqual, 2)
+		})
+		Convey("Inexisting Game", func() {
+			_, err := svc.LoadGame(ctx, "missing")
+			So(err, ShouldResemble, Error{StatusCode: http.StatusInternalServerError, Message: "Game not found"})
+		})
+		Convey("Click", func() {
+			game, err := svc.Click(ctx, models.ClickRequest{Name: "remote", Row: 0, Column: 2})
+			So(err, ShouldBeNil)
+			So(game.Board[0][2].Clicked, ShouldBeTrue)
+			So(game.Discovered, ShouldEqual, 4)
+		})
+		Convey("Replay", func() {
+			replay, err := svc.Replay(ctx, "remote")
+			So(err, ShouldBeNil)
+			So(replay.Board, ShouldBeNil)
+			game, err := svc.Surrender(ctx, "remote")
+			So(err, ShouldBeNil)
+			So(game.Board[0][0].Mine, ShouldBeTrue)
+			replay, err = svc.Replay(ctx, "remote")
+			So(err, ShouldBeNil)
+			So(len(replay.Events), ShouldEqual, 3)
+		})
+		Convey("Export Layout", func() {
+			layout, err := svc.ExportLayout(ctx, "remote")
+			So(err, ShouldBeNil)
+			So(layout, ShouldEqual, models.Layout("*..\n...\n..*\n"))
+		})
+		Convey("Save Game", func() {
+			So(svc.SaveGame(ctx, &models.Game{Name: "remote"}), ShouldEqual, ErrNotSupported)
+		})
+		Convey("List Games", func() {
+			page, err := svc.ListGames(ctx, models.GameQuery{Status: []models.Status{models.StatusAbandoned}, Sort: "name"})
+			So(err, ShouldBeNil)
+			So(len(page.Games), ShouldEqual, 1)
+			So(page.Games[0].Name, ShouldEqual, "remote")
+			So(page.Games[0].Board, ShouldBeNil)
+		})
+		Convey("Delete Game", func() {
+			So(svc.DeleteGame(ctx, models.DeleteGameRequest{Name: "remote"}), ShouldBeNil)
+			_, err := svc.LoadGame(ctx, "remote")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestOptions(t *testing.T) {
+
+	server := newServer()
+	defer server.Close()
+
+	Convey("Test Options", t, func() {
+		Convey("Headers", func() {
+			var auth string
+			handler := server.Config.Handler
+			headers := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				handler.ServeHTTP(w, r)
+			}))
+			defer headers.Close()
+
+			svc, _ := New(headers.URL, Header("Authorization", "Bearer token"))
+			svc.GetMinesweeper(context.TODO())
+			So(auth, ShouldEqual, "Bearer token")
+		})
+		Convey("Retries", func() {
+			//the first attempts drop the connection, as an unreachable service would
+			attempts := 0
+			handler := server.Config.Handler
+			flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts < 3 {
+					conn, _, _ := w.(http.Hijacker).Hijack()
+					conn.Close()
+					return
+				}
+				handler.ServeHTTP(w, r)
+			}))
+			defer flaky.Close()
+
+			svc, _ := New(flaky.URL, Retries(3))
+			res, err := svc.GetMinesweeper(context.TODO())
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldNotBeEmpty)
+			So(attempts, ShouldEqual, 3)
+		})
+		Convey("Moves aren't retried", func() {
+			attempts := 0
+			dropped := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			}))
+			defer dropped.Close()
+
+			svc, _ := New(dropped.URL, Retries(3))
+			_, err := svc.Flag(context.TODO(), models.ClickRequest{Name: "minetest", Row: 0, Column: 0})
+			So(err, ShouldNotBeNil)
+			//closing the server waits for its handlers, so attempts is counted in full
+			dropped.Close()
+			So(attempts, ShouldEqual, 1)
+		})
+		Convey("Timeout", func() {
+			slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			}))
+			defer slow.Close()
+
+			svc, _ := New(slow.URL, Timeout(50*time.Millisecond))
+			_, err := svc.GetMinesweeper(context.TODO())
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestSharedGame(t *testing.T) {
+
+	server := newServer()
+	defer server.Close()
+
+	svc, err := New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.TODO()
+	svc.NewGame(ctx, &models.Game{Name: "shared", Layout: "*...\n....\n....\n...*", Mode: models.ModeCoop})
+	alice, _ := svc.JoinGame(ctx, models.JoinGameRequest{Name: "shared", Player: "alice"})
+	svc.JoinGame(ctx, models.JoinGameRequest{Name: "shared", Player: "bob"})
+
+	Convey("Test Shared Game", t, func() {
+		Convey("Moves need a token", func() {
+			_, err := svc.Click(ctx, models.ClickRequest{Name: "shared", Row: 3, Column: 0})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Moves are pushed and attributed", func() {
+			watching, cancel := context.WithCancel(ctx)
+			defer cancel()
+			updates, err := svc.Subscribe(watching, "shared")
+			So(err, ShouldBeNil)
+
+			game, err := svc.Click(service.WithToken(ctx, alice.Token), models.ClickRequest{Name: "shared", Row: 3, Column: 0})
+			So(err, ShouldBeNil)
+			So(game.Stats[0].Clicks, ShouldEqual, 1)
+			So(game.Stats[0].Revealed, ShouldEqual, game.Discovered)
+
+			select {
+			case update := <-updates:
+				So(update.Event.Player, ShouldEqual, "alice")
+				So(update.Game.Discovered, ShouldEqual, game.Discovered)
+			case <-time.After(time.Second):
+				t.Error("no update was pushed")
+			}
+		})
+	})
+
+}
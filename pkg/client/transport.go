@@ -0,0 +1,626 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minesweeper/pkg/endpoints"
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/service"
+)
+
+const maxEventSize = 4 << 20 //Biggest server-sent event read, enough for an update with the largest board
+
+//setPath appends the path of the route to the base path of the service
+func setPath(r *http.Request, segments ...string) {
+
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	route := "/" + strings.Join(segments, "/")
+	r.URL.RawPath = r.URL.EscapedPath() + route
+	r.URL.Path, _ = url.PathUnescape(r.URL.RawPath)
+}
+
+//encodeJSON writes the request body as JSON
+func encodeJSON(r *http.Request, body interface{}) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	r.ContentLength = int64(buf.Len())
+	r.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+//responseError returns the error the service answered with, if the response is one.
+//pkg/http answers errors with their message as the body
+func responseError(r *http.Response) error {
+
+	if r.StatusCode >= 200 && r.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := ioutil.ReadAll(r.Body)
+	return Error{StatusCode: r.StatusCode, Message: strings.TrimSpace(string(msg))}
+}
+
+func encodeGetMinesweeperRequest(_ context.Context, r *http.Request, _ interface{}) error {
+	setPath(r, "minesweeper")
+	return nil
+}
+
+func decodeGetMinesweeperResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.GetMinesweeperResponse{Err: err}, nil
+	}
+	var res service.MinesweeperResponse
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.GetMinesweeperResponse{Res: res}, nil
+}
+
+func encodeNewGameRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games")
+	return encodeJSON(r, request.(endpoints.NewGameRequest).Req)
+}
+
+func decodeNewGameResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	return endpoints.NewGameResponse{Err: responseError(r)}, nil
+}
+
+func encodeLoadGameRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.LoadGameRequest).Req)
+	return nil
+}
+
+func decodeLoadGameResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.LoadGameResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.LoadGameResponse{Res: &res}, nil
+}
+
+func encodeListGamesRequest(_ context.Context, r *http.Request, request interface{}) error {
+
+	setPath(r, "minesweeper", "games")
+	query := request.(endpoints.ListGamesRequest).Req
+	params := url.Values{}
+	if query.Owner != "" {
+		params.Set("owner", query.Owner)
+	}
+	for _, status := range query.Status {
+		params.Add("status", string(status))
+	}
+	if query.From != nil {
+		params.Set("from", query.From.Format(time.RFC3339))
+	}
+	if query.To != nil {
+		params.Set("to", query.To.Format(time.RFC3339))
+	}
+	if query.Sort != "" {
+		params.Set("sort", query.Sort)
+	}
+	if query.Limit != 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Cursor != "" {
+		params.Set("cursor", query.Cursor)
+	}
+	r.URL.RawQuery = params.Encode()
+	return nil
+}
+
+func decodeListGamesResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ListGamesResponse{Res: &models.GamePage{}, Err: err}, nil
+	}
+	var res models.GamePage
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.ListGamesResponse{Res: &res}, nil
+}
+
+func encodeDeleteGameRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.DeleteGameRequest).Req
+	setPath(r, "minesweeper", "games", req.Name)
+	if req.Player != "" {
+		r.URL.RawQuery = url.Values{"player": {req.Player}}.Encode()
+	}
+	return nil
+}
+
+func decodeDeleteGameResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	return endpoints.DeleteGameResponse{Err: responseError(r)}, nil
+}
+
+func encodeClickRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games")
+	return encodeJSON(r, request.(endpoints.ClickRequest).Req)
+}
+
+func decodeClickResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ClickResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.ClickResponse{Res: &res}, nil
+}
+
+func encodeFlagRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.FlagRequest).Req
+	setPath(r, "minesweeper", "games", req.Name, "flag")
+	return encodeJSON(r, req)
+}
+
+func encodeChordRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.ChordRequest).Req
+	setPath(r, "minesweeper", "games", req.Name, "chord")
+	return encodeJSON(r, req)
+}
+
+func decodeFlagResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.FlagResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.FlagResponse{Res: &res}, nil
+}
+
+func decodeChordResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ChordResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.ChordResponse{Res: &res}, nil
+}
+
+func encodeReplayRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.ReplayRequest).Req, "replay")
+	return nil
+}
+
+func decodeReplayResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ReplayResponse{Res: &models.Replay{}, Err: err}, nil
+	}
+	var res models.Replay
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.ReplayResponse{Res: &res}, nil
+}
+
+func encodeExportLayoutRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.ExportLayoutRequest).Req, "layout")
+	return nil
+}
+
+func decodeExportLayoutResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ExportLayoutResponse{Err: err}, nil
+	}
+	layout, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return endpoints.ExportLayoutResponse{Res: models.Layout(layout)}, nil
+}
+
+func encodeHintRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.HintRequest).Req, "hint")
+	return nil
+}
+
+func decodeHintResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.HintResponse{Res: &models.Hint{}, Err: err}, nil
+	}
+	var res models.Hint
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.HintResponse{Res: &res}, nil
+}
+
+func encodeUndoRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.UndoRequest).Req, "undo")
+	return nil
+}
+
+func decodeUndoResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.UndoResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.UndoResponse{Res: &res}, nil
+}
+
+func encodeCreateMatchRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "matches")
+	return encodeJSON(r, request.(endpoints.CreateMatchRequest).Req)
+}
+
+func decodeCreateMatchResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.CreateMatchResponse{Res: &models.Match{}, Err: err}, nil
+	}
+	var res models.Match
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.CreateMatchResponse{Res: &res}, nil
+}
+
+func encodeJoinMatchRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.JoinMatchRequest).Req
+	setPath(r, "minesweeper", "matches", req.Match, "players")
+	return encodeJSON(r, req)
+}
+
+func decodeJoinMatchResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.JoinMatchResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.JoinMatchResponse{Res: &res}, nil
+}
+
+func encodeStartMatchRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "matches", request.(endpoints.StartMatchRequest).Req, "start")
+	return nil
+}
+
+func decodeStartMatchResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.StartMatchResponse{Res: &models.Match{}, Err: err}, nil
+	}
+	var res models.Match
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.StartMatchResponse{Res: &res}, nil
+}
+
+func encodeStandingsRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "matches", request.(endpoints.StandingsRequest).Req)
+	return nil
+}
+
+func decodeStandingsResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.StandingsResponse{Res: &models.Standings{}, Err: err}, nil
+	}
+	var res models.Standings
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.StandingsResponse{Res: &res}, nil
+}
+
+func encodeJoinGameRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.JoinGameRequest).Req
+	setPath(r, "minesweeper", "games", req.Name, "players")
+	return encodeJSON(r, req)
+}
+
+func decodeJoinGameResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.JoinGameResponse{Res: &models.Session{}, Err: err}, nil
+	}
+	var res models.Session
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.JoinGameResponse{Res: &res}, nil
+}
+
+func encodeScoresRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.ScoresRequest).Req, "scores")
+	return nil
+}
+
+func decodeScoresResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ScoresResponse{Res: &models.Scoreboard{}, Err: err}, nil
+	}
+	var res models.Scoreboard
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.ScoresResponse{Res: &res}, nil
+}
+
+func encodeRatingRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "ratings", request.(endpoints.RatingRequest).Req)
+	return nil
+}
+
+func decodeRatingResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.RatingResponse{Res: &models.PlayerRating{}, Err: err}, nil
+	}
+	var res models.PlayerRating
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.RatingResponse{Res: &res}, nil
+}
+
+func encodeLadderRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "ratings")
+	return nil
+}
+
+func decodeLadderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.LadderResponse{Err: err}, nil
+	}
+	var res []models.PlayerRating
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.LadderResponse{Res: res}, nil
+}
+
+func encodeQueueRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "queue")
+	return encodeJSON(r, request.(endpoints.QueueRequest).Req)
+}
+
+func decodeQueueResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.QueueResponse{Res: &models.Ticket{}, Err: err}, nil
+	}
+	var res models.Ticket
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.QueueResponse{Res: &res}, nil
+}
+
+func encodeTicketRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "queue", request.(endpoints.TicketRequest).Req)
+	return nil
+}
+
+func decodeTicketResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.TicketResponse{Res: &models.Ticket{}, Err: err}, nil
+	}
+	var res models.Ticket
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.TicketResponse{Res: &res}, nil
+}
+
+func encodeDailyRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "daily")
+	r.URL.RawQuery = url.Values{"player": {request.(endpoints.DailyRequest).Req}}.Encode()
+	return nil
+}
+
+func decodeDailyResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.DailyResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.DailyResponse{Res: &res}, nil
+}
+
+func encodeLeaderboardRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "daily", "leaderboard")
+	if date := request.(endpoints.LeaderboardRequest).Req; date != "" {
+		r.URL.RawQuery = url.Values{"date": {date}}.Encode()
+	}
+	return nil
+}
+
+func decodeLeaderboardResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.LeaderboardResponse{Res: &models.Leaderboard{}, Err: err}, nil
+	}
+	var res models.Leaderboard
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.LeaderboardResponse{Res: &res}, nil
+}
+
+func encodeLayerRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(endpoints.LayerRequest).Req
+	setPath(r, "minesweeper", "games", req.Name, "layers", strconv.Itoa(req.Layer))
+	return nil
+}
+
+func decodeLayerResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.LayerResponse{Res: &models.Slice{}, Err: err}, nil
+	}
+	var res models.Slice
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.LayerResponse{Res: &res}, nil
+}
+
+func encodePauseRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.PauseRequest).Req, "pause")
+	return nil
+}
+
+func decodePauseResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.PauseResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.PauseResponse{Res: &res}, nil
+}
+
+func encodeResumeRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.ResumeRequest).Req, "resume")
+	return nil
+}
+
+func decodeResumeResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.ResumeResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.ResumeResponse{Res: &res}, nil
+}
+
+func encodeSurrenderRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.SurrenderRequest).Req, "surrender")
+	return nil
+}
+
+func decodeSurrenderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.SurrenderResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.SurrenderResponse{Res: &res}, nil
+}
+
+func encodeRestartRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.RestartRequest).Req, "restart")
+	return nil
+}
+
+func decodeRestartResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.RestartResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.RestartResponse{Res: &res}, nil
+}
+
+func encodeRetryRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.RetryRequest).Req, "retry")
+	return nil
+}
+
+func decodeRetryResponse(_ context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		return endpoints.RetryResponse{Res: &models.Game{}, Err: err}, nil
+	}
+	var res models.Game
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return endpoints.RetryResponse{Res: &res}, nil
+}
+
+func encodeSubscribeRequest(_ context.Context, r *http.Request, request interface{}) error {
+	setPath(r, "minesweeper", "games", request.(endpoints.SubscribeRequest).Req, "events")
+	r.Header.Set("Accept", "text/event-stream")
+	return nil
+}
+
+//decodeSubscribeResponse reads the server-sent events of the stream as they come, and closes the body once it ends
+func decodeSubscribeResponse(ctx context.Context, r *http.Response) (interface{}, error) {
+
+	if err := responseError(r); err != nil {
+		r.Body.Close()
+		return endpoints.SubscribeResponse{Err: err}, nil
+	}
+	updates := make(chan models.Update)
+	go func() {
+		defer close(updates)
+		defer r.Body.Close()
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(nil, maxEventSize)
+		for scanner.Scan() {
+			data := strings.TrimPrefix(scanner.Text(), "data:")
+			if data == scanner.Text() {
+				continue
+			}
+			var update models.Update
+			if err := json.Unmarshal([]byte(data), &update); err != nil {
+				return
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return endpoints.SubscribeResponse{Res: updates}, nil
+}
@@ -0,0 +1,25 @@
+package db
+
+//DailyDBManager is the interface that express the operations needed to keep track of the games of each daily challenge
+type DailyDBManager interface {
+	AddDaily(date string, name string) error
+	GetDailies(date string) []string
+}
+
+//AddDaily adds a game to the daily challenge of a day
+func (ms *MineStorage) AddDaily(date string, name string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.dailies[date] = append(ms.dailies[date], name)
+
+	return nil
+}
+
+//GetDailies obtains the names of the games of the daily challenge of a day, in the order they were created
+func (ms *MineStorage) GetDailies(date string) []string {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return append([]string(nil), ms.dailies[date]...)
+}
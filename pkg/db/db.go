@@ -1,9 +1,15 @@
 package db
 
 import (
+	"encoding/base64"
 	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
 )
 
 //MineDBManager is the interface that express the operations needed to manage the storage of the minesweeper. Its isolated from its implementation
@@ -11,51 +17,342 @@ type MineDBManager interface {
 	InsertGame(game *models.Game) error
 	UpdateGame(game *models.Game) error
 	GetGame(name string) (*models.Game, error)
+	ListGames(query models.GameQuery) (*models.GamePage, error)
+	DeleteGame(name string) error
+	InsertReplay(replay *models.Replay) error
+	AppendEvent(name string, event models.Event) error
+	GetReplay(name string) (*models.Replay, error)
+	PushHistory(game *models.Game, limit int) error
+	PopHistory(name string) (*models.Game, error)
+	LockGame(name string) (unlock func())
 }
 
-//MineStorage implements MineDBManager. For now it just contains a map of strings and games.
+//MineStorage implements MineDBManager. For now it just contains a map of strings and games, guarded for concurrent use.
+//Games are copied in and out, so changing a game only takes effect once it's updated.
 //In a future version, it could have a real db client and implement the interface around that
 type MineStorage struct {
-	data map[string]*models.Game
+	mu      sync.RWMutex
+	data    map[string]*models.Game
+	replays map[string]*models.Replay
+	history map[string][]*models.Game
+	matches map[string]*models.Match
+	locks   map[string]*gameLock
+	ratings map[string]*models.PlayerRating
+	queue   []models.Ticket
+	dailies map[string][]string
 }
 
 //New creates a new MineStorage and instantiates the data parameter of it
 func New() *MineStorage {
 	ms := MineStorage{
-		data: make(map[string]*models.Game),
+		data:    make(map[string]*models.Game),
+		replays: make(map[string]*models.Replay),
+		history: make(map[string][]*models.Game),
+		matches: make(map[string]*models.Match),
+		locks:   make(map[string]*gameLock),
+		ratings: make(map[string]*models.PlayerRating),
+		dailies: make(map[string][]string),
 	}
 	return &ms
 }
 
 //InsertGame checks that there's no other game stored with the same name as the new game. Afterwards, its saved in the map
-func (ms MineStorage) InsertGame(game *models.Game) error {
+func (ms *MineStorage) InsertGame(game *models.Game) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
 
 	if _, ok := ms.data[game.Name]; ok {
 		return errors.New("Name already used")
 	}
-	ms.data[game.Name] = game
+	ms.data[game.Name] = clone(game)
 
 	return nil
 }
 
 //UpdateGame ensures a game with the update exists already and after that updates it
-func (ms MineStorage) UpdateGame(game *models.Game) error {
+func (ms *MineStorage) UpdateGame(game *models.Game) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
 
 	if _, ok := ms.data[game.Name]; !ok {
 		return errors.New("Game not found")
 	}
-	ms.data[game.Name] = game
+	ms.data[game.Name] = clone(game)
 
 	return nil
 }
 
 //GetGame obtains a game from the map according to its name
-func (ms MineStorage) GetGame(name string) (*models.Game, error) {
+func (ms *MineStorage) GetGame(name string) (*models.Game, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
 
 	if _, ok := ms.data[name]; !ok {
 		return &models.Game{}, errors.New("Game not found")
 	}
-	resp := ms.data[name]
+	resp := clone(ms.data[name])
 	return resp, nil
 
 }
+
+//ListGames obtains a page of the games a query picks, in its order and without their boards. The query is taken as it is:
+//its sort is created or name, with an optional leading -, and its limit is above 0
+func (ms *MineStorage) ListGames(query models.GameQuery) (*models.GamePage, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	field, desc := strings.TrimPrefix(query.Sort, "-"), strings.HasPrefix(query.Sort, "-")
+	//before tells whether a game goes before another in the order of the query. Names are unique, so they settle ties
+	before := func(a *models.Game, b *models.Game) bool {
+		if field == models.SortCreated && !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created) != desc
+		}
+		return a.Name != b.Name && (a.Name < b.Name) != desc
+	}
+	var after *models.Game
+	if query.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(query.Cursor); err != nil {
+			return &models.GamePage{}, err
+		}
+	}
+
+	games := []*models.Game{}
+	for _, game := range ms.data {
+		if matches(game, query) && (after == nil || before(after, game)) {
+			games = append(games, game)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return before(games[i], games[j])
+	})
+	page := &models.GamePage{Games: []models.Game{}}
+	for i, game := range games {
+		if i == query.Limit {
+			page.Next = encodeCursor(games[i-1])
+			break
+		}
+		cp := clone(game)
+		cp.Board = nil
+		page.Games = append(page.Games, *cp)
+	}
+	return page, nil
+}
+
+//DeleteGame removes a game from the map, along with its replay and its history. Its lock goes once no one holds it
+func (ms *MineStorage) DeleteGame(name string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.data[name]; !ok {
+		return errors.New("Game not found")
+	}
+	delete(ms.data, name)
+	delete(ms.replays, name)
+	delete(ms.history, name)
+
+	return nil
+}
+
+//InsertReplay stores the initial state of a game, from which its events will be replayed. Only one replay can exist for each game
+func (ms *MineStorage) InsertReplay(replay *models.Replay) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.replays[replay.Name]; ok {
+		return errors.New("Name already used")
+	}
+	ms.replays[replay.Name] = replay
+
+	return nil
+}
+
+//AppendEvent adds a new event at the end of the replay of a game
+func (ms *MineStorage) AppendEvent(name string, event models.Event) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	replay, ok := ms.replays[name]
+	if !ok {
+		return errors.New("Game not found")
+	}
+	replay.Events = append(replay.Events, event)
+
+	return nil
+}
+
+//GetReplay obtains a copy of the replay of a game according to its name
+func (ms *MineStorage) GetReplay(name string) (*models.Replay, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if _, ok := ms.replays[name]; !ok {
+		return &models.Replay{}, errors.New("Game not found")
+	}
+	//events keep being appended to the stored replay, so a copy of them is returned
+	resp := *ms.replays[name]
+	resp.Events = append([]models.Event(nil), resp.Events...)
+	return &resp, nil
+
+}
+
+//PushHistory stores a past state of a game on top of its history, keeping only the latest limit states
+func (ms *MineStorage) PushHistory(game *models.Game, limit int) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.data[game.Name]; !ok {
+		return errors.New("Game not found")
+	}
+	history := append(ms.history[game.Name], game)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	ms.history[game.Name] = history
+
+	return nil
+}
+
+//PopHistory takes the latest past state of a game out of its history
+func (ms *MineStorage) PopHistory(name string) (*models.Game, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	history := ms.history[name]
+	if len(history) == 0 {
+		return &models.Game{}, errors.New("Nothing to undo")
+	}
+	resp := history[len(history)-1]
+	ms.history[name] = history[:len(history)-1]
+	return resp, nil
+
+}
+
+//gameLock is the lock of a game, kept while someone holds it or waits for it
+type gameLock struct {
+	sync.Mutex
+	users int
+}
+
+//LockGame holds a game for the caller until unlock is called, so concurrent moves on the same game are made one after the other.
+//Locks are dropped once the last user unlocks them, so deleted games and games no one plays don't keep theirs
+func (ms *MineStorage) LockGame(name string) (unlock func()) {
+	ms.mu.Lock()
+	lock, ok := ms.locks[name]
+	if !ok {
+		lock = &gameLock{}
+		ms.locks[name] = lock
+	}
+	lock.users++
+	ms.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		ms.mu.Lock()
+		defer ms.mu.Unlock()
+		lock.users--
+		if lock.users == 0 {
+			delete(ms.locks, name)
+		}
+	}
+}
+
+//clone returns a deep copy of a game, so the stored game and the one the caller has don't share anything
+func clone(game *models.Game) *models.Game {
+
+	cp := *game
+	cp.Board = make([]models.CellRow, len(game.Board))
+	for i, row := range game.Board {
+		cp.Board[i] = append(models.CellRow(nil), row...)
+	}
+	cp.Start = copyTime(game.Start)
+	cp.Deadline = copyTime(game.Deadline)
+	cp.PausedAt = copyTime(game.PausedAt)
+	cp.Stats = append([]models.PlayerStats(nil), game.Stats...)
+	cp.Scores = append([]models.Score(nil), game.Scores...)
+	cp.Offsets = append([]topology.Cell(nil), game.Offsets...)
+	if game.Geometry != nil {
+		geometry := *game.Geometry
+		cp.Geometry = &geometry
+	}
+	if game.Tokens != nil {
+		cp.Tokens = make(map[string]string, len(game.Tokens))
+		for token, player := range game.Tokens {
+			cp.Tokens[token] = player
+		}
+	}
+	return &cp
+}
+
+//copyTime returns a pointer to a copy of a time, or nil for no time
+func copyTime(t *time.Time) *time.Time {
+
+	if t == nil {
+		return nil
+	}
+	cp := *t
+	return &cp
+}
+
+//matches tells whether a game passes the filters of a query
+func matches(game *models.Game, query models.GameQuery) bool {
+
+	if query.Owner != "" && !owns(game, query.Owner) {
+		return false
+	}
+	if len(query.Status) > 0 {
+		found := false
+		for _, status := range query.Status {
+			found = found || game.Status == status
+		}
+		if !found {
+			return false
+		}
+	}
+	if query.From != nil && game.Created.Before(*query.From) {
+		return false
+	}
+	if query.To != nil && !game.Created.Before(*query.To) {
+		return false
+	}
+	return true
+}
+
+//owns tells whether a player is the player of a game, or one of the players of a shared game
+func owns(game *models.Game, player string) bool {
+
+	if game.Player == player {
+		return true
+	}
+	for _, stats := range game.Stats {
+		if stats.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+//encodeCursor writes where a page ends, as the time and name of its last game, so the next page starts after it
+//even if games were created or deleted meanwhile
+func encodeCursor(game *models.Game) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(game.Created.Format(time.RFC3339Nano) + " " + game.Name))
+}
+
+//decodeCursor reads the time and name a cursor holds, as a game
+func decodeCursor(cursor string) (*models.Game, error) {
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("Invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), " ", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("Invalid cursor")
+	}
+	created, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, errors.New("Invalid cursor")
+	}
+	return &models.Game{Name: parts[1], Created: created}, nil
+}
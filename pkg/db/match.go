@@ -0,0 +1,63 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+//MatchDBManager is the interface that express the operations needed to manage the storage of matches.
+//Players join and finish matches at the same time, so changes are made through UpdateMatch, which applies them atomically
+type MatchDBManager interface {
+	InsertMatch(match *models.Match) error
+	GetMatch(name string) (*models.Match, error)
+	UpdateMatch(name string, update func(match *models.Match) error) error
+}
+
+//InsertMatch checks that there's no other match stored with the same name as the new match. Afterwards, its saved in the map
+func (ms *MineStorage) InsertMatch(match *models.Match) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.matches[match.Name]; ok {
+		return errors.New("Name already used")
+	}
+	ms.matches[match.Name] = match
+
+	return nil
+}
+
+//GetMatch obtains a copy of a match according to its name
+func (ms *MineStorage) GetMatch(name string) (*models.Match, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if _, ok := ms.matches[name]; !ok {
+		return &models.Match{}, errors.New("Match not found")
+	}
+	//players keep joining the stored match, so a copy of its entries is returned
+	resp := *ms.matches[name]
+	resp.Entries = append([]models.MatchEntry(nil), resp.Entries...)
+	return &resp, nil
+
+}
+
+//UpdateMatch calls update with the stored match, holding the storage so no other change happens meanwhile.
+//If update returns an error, the match is left as it was
+func (ms *MineStorage) UpdateMatch(name string, update func(match *models.Match) error) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	stored, ok := ms.matches[name]
+	if !ok {
+		return errors.New("Match not found")
+	}
+	match := *stored
+	match.Entries = append([]models.MatchEntry(nil), stored.Entries...)
+	if err := update(&match); err != nil {
+		return err
+	}
+	ms.matches[name] = &match
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"github.com/minesweeper/pkg/models"
+)
+
+//RatingDBManager is the interface that express the operations needed to manage the storage of ratings and of the ranked queue.
+//Ratings of every player of a match change together, so they are changed through UpdateRatings, which applies them atomically
+type RatingDBManager interface {
+	GetRating(player string) (*models.PlayerRating, bool)
+	GetRatings() []*models.PlayerRating
+	UpdateRatings(players []string, update func(ratings []*models.PlayerRating) error) error
+	UpdateQueue(update func(queue []models.Ticket) ([]models.Ticket, error)) error
+}
+
+//GetRating obtains a copy of the rating of a player, and whether the player has one
+func (ms *MineStorage) GetRating(player string) (*models.PlayerRating, bool) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	stored, ok := ms.ratings[player]
+	if !ok {
+		return &models.PlayerRating{Player: player}, false
+	}
+	return copyRating(stored), true
+}
+
+//GetRatings obtains a copy of the ratings of every rated player, without their history
+func (ms *MineStorage) GetRatings() []*models.PlayerRating {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	resp := make([]*models.PlayerRating, 0, len(ms.ratings))
+	for _, stored := range ms.ratings {
+		rating := *stored
+		rating.History = nil
+		resp = append(resp, &rating)
+	}
+	return resp
+}
+
+//UpdateRatings calls update with the ratings of the given players, in the same order, holding the storage so no other change happens meanwhile.
+//Players without a rating are passed with just their name. If update returns an error, the ratings are left as they were
+func (ms *MineStorage) UpdateRatings(players []string, update func(ratings []*models.PlayerRating) error) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ratings := make([]*models.PlayerRating, len(players))
+	for i, player := range players {
+		ratings[i] = &models.PlayerRating{Player: player}
+		if stored, ok := ms.ratings[player]; ok {
+			ratings[i] = copyRating(stored)
+		}
+	}
+	if err := update(ratings); err != nil {
+		return err
+	}
+	for _, rating := range ratings {
+		ms.ratings[rating.Player] = rating
+	}
+
+	return nil
+}
+
+//UpdateQueue calls update with the tickets of the ranked queue, holding the storage so no other change happens meanwhile,
+//and stores the tickets it returns. If update returns an error, the queue is left as it was
+func (ms *MineStorage) UpdateQueue(update func(queue []models.Ticket) ([]models.Ticket, error)) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	queue, err := update(append([]models.Ticket(nil), ms.queue...))
+	if err != nil {
+		return err
+	}
+	ms.queue = queue
+
+	return nil
+}
+
+func copyRating(rating *models.PlayerRating) *models.PlayerRating {
+
+	resp := *rating
+	resp.History = append([]models.RatingChange(nil), rating.History...)
+	return &resp
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/render"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
@@ -18,7 +19,35 @@ type Endpoints struct {
 	NewGameEndpoint        endpoint.Endpoint
 	LoadGameEndpoint       endpoint.Endpoint
 	SaveGameEndpoint       endpoint.Endpoint
+	ListGamesEndpoint      endpoint.Endpoint
+	DeleteGameEndpoint     endpoint.Endpoint
 	ClickEndpoint          endpoint.Endpoint
+	FlagEndpoint           endpoint.Endpoint
+	ChordEndpoint          endpoint.Endpoint
+	ReplayEndpoint         endpoint.Endpoint
+	ExportLayoutEndpoint   endpoint.Endpoint
+	ImageEndpoint          endpoint.Endpoint
+	HintEndpoint           endpoint.Endpoint
+	UndoEndpoint           endpoint.Endpoint
+	CreateMatchEndpoint    endpoint.Endpoint
+	JoinMatchEndpoint      endpoint.Endpoint
+	StartMatchEndpoint     endpoint.Endpoint
+	StandingsEndpoint      endpoint.Endpoint
+	JoinGameEndpoint       endpoint.Endpoint
+	SubscribeEndpoint      endpoint.Endpoint
+	ScoresEndpoint         endpoint.Endpoint
+	RatingEndpoint         endpoint.Endpoint
+	LadderEndpoint         endpoint.Endpoint
+	QueueEndpoint          endpoint.Endpoint
+	TicketEndpoint         endpoint.Endpoint
+	DailyEndpoint          endpoint.Endpoint
+	LeaderboardEndpoint    endpoint.Endpoint
+	LayerEndpoint          endpoint.Endpoint
+	PauseEndpoint          endpoint.Endpoint
+	ResumeEndpoint         endpoint.Endpoint
+	SurrenderEndpoint      endpoint.Endpoint
+	RestartEndpoint        endpoint.Endpoint
+	RetryEndpoint          endpoint.Endpoint
 }
 
 // New will create an Endpoints struct with initialized endpoint(s) and
@@ -43,9 +72,99 @@ func New(svc service.Minesweepersvc, logger log.Logger) (ep Endpoints) {
 	ep.SaveGameEndpoint = MakeSaveGameEndpoint(svc)
 	ep.SaveGameEndpoint = LoggingMiddleware(log.With(logger, "method", "SaveGame"))(ep.SaveGameEndpoint)
 
+	//create the ListGames endpoint
+	ep.ListGamesEndpoint = MakeListGamesEndpoint(svc)
+	ep.ListGamesEndpoint = LoggingMiddleware(log.With(logger, "method", "ListGames"))(ep.ListGamesEndpoint)
+
+	//create the DeleteGame endpoint
+	ep.DeleteGameEndpoint = MakeDeleteGameEndpoint(svc)
+	ep.DeleteGameEndpoint = LoggingMiddleware(log.With(logger, "method", "DeleteGame"))(ep.DeleteGameEndpoint)
+
 	//create the Click endpoint
 	ep.ClickEndpoint = MakeClickEndpoint(svc)
 	ep.ClickEndpoint = LoggingMiddleware(log.With(logger, "method", "Click"))(ep.ClickEndpoint)
+
+	//create the Flag endpoint
+	ep.FlagEndpoint = MakeFlagEndpoint(svc)
+	ep.FlagEndpoint = LoggingMiddleware(log.With(logger, "method", "Flag"))(ep.FlagEndpoint)
+
+	//create the Chord endpoint
+	ep.ChordEndpoint = MakeChordEndpoint(svc)
+	ep.ChordEndpoint = LoggingMiddleware(log.With(logger, "method", "Chord"))(ep.ChordEndpoint)
+
+	//create the Replay endpoint
+	ep.ReplayEndpoint = MakeReplayEndpoint(svc)
+	ep.ReplayEndpoint = LoggingMiddleware(log.With(logger, "method", "Replay"))(ep.ReplayEndpoint)
+
+	//create the ExportLayout endpoint
+	ep.ExportLayoutEndpoint = MakeExportLayoutEndpoint(svc)
+	ep.ExportLayoutEndpoint = LoggingMiddleware(log.With(logger, "method", "ExportLayout"))(ep.ExportLayoutEndpoint)
+
+	//create the Image endpoint
+	ep.ImageEndpoint = MakeImageEndpoint(svc)
+	ep.ImageEndpoint = LoggingMiddleware(log.With(logger, "method", "Image"))(ep.ImageEndpoint)
+
+	//create the Hint endpoint
+	ep.HintEndpoint = MakeHintEndpoint(svc)
+	ep.HintEndpoint = LoggingMiddleware(log.With(logger, "method", "Hint"))(ep.HintEndpoint)
+
+	//create the Undo endpoint
+	ep.UndoEndpoint = MakeUndoEndpoint(svc)
+	ep.UndoEndpoint = LoggingMiddleware(log.With(logger, "method", "Undo"))(ep.UndoEndpoint)
+
+	//create the match endpoints
+	ep.CreateMatchEndpoint = MakeCreateMatchEndpoint(svc)
+	ep.CreateMatchEndpoint = LoggingMiddleware(log.With(logger, "method", "CreateMatch"))(ep.CreateMatchEndpoint)
+	ep.JoinMatchEndpoint = MakeJoinMatchEndpoint(svc)
+	ep.JoinMatchEndpoint = LoggingMiddleware(log.With(logger, "method", "JoinMatch"))(ep.JoinMatchEndpoint)
+	ep.StartMatchEndpoint = MakeStartMatchEndpoint(svc)
+	ep.StartMatchEndpoint = LoggingMiddleware(log.With(logger, "method", "StartMatch"))(ep.StartMatchEndpoint)
+	ep.StandingsEndpoint = MakeStandingsEndpoint(svc)
+	ep.StandingsEndpoint = LoggingMiddleware(log.With(logger, "method", "Standings"))(ep.StandingsEndpoint)
+
+	//create the shared game endpoints
+	ep.JoinGameEndpoint = MakeJoinGameEndpoint(svc)
+	ep.JoinGameEndpoint = LoggingMiddleware(log.With(logger, "method", "JoinGame"))(ep.JoinGameEndpoint)
+	ep.SubscribeEndpoint = MakeSubscribeEndpoint(svc)
+	ep.SubscribeEndpoint = LoggingMiddleware(log.With(logger, "method", "Subscribe"))(ep.SubscribeEndpoint)
+
+	//create the flags game endpoints
+	ep.ScoresEndpoint = MakeScoresEndpoint(svc)
+	ep.ScoresEndpoint = LoggingMiddleware(log.With(logger, "method", "Scores"))(ep.ScoresEndpoint)
+
+	//create the rating endpoints
+	ep.RatingEndpoint = MakeRatingEndpoint(svc)
+	ep.RatingEndpoint = LoggingMiddleware(log.With(logger, "method", "Rating"))(ep.RatingEndpoint)
+	ep.LadderEndpoint = MakeLadderEndpoint(svc)
+	ep.LadderEndpoint = LoggingMiddleware(log.With(logger, "method", "Ladder"))(ep.LadderEndpoint)
+	ep.QueueEndpoint = MakeQueueEndpoint(svc)
+	ep.QueueEndpoint = LoggingMiddleware(log.With(logger, "method", "Queue"))(ep.QueueEndpoint)
+	ep.TicketEndpoint = MakeTicketEndpoint(svc)
+	ep.TicketEndpoint = LoggingMiddleware(log.With(logger, "method", "Ticket"))(ep.TicketEndpoint)
+
+	//create the daily challenge endpoints
+	ep.DailyEndpoint = MakeDailyEndpoint(svc)
+	ep.DailyEndpoint = LoggingMiddleware(log.With(logger, "method", "Daily"))(ep.DailyEndpoint)
+	ep.LeaderboardEndpoint = MakeLeaderboardEndpoint(svc)
+	ep.LeaderboardEndpoint = LoggingMiddleware(log.With(logger, "method", "DailyLeaderboard"))(ep.LeaderboardEndpoint)
+
+	//create the cube board endpoints
+	ep.LayerEndpoint = MakeLayerEndpoint(svc)
+	ep.LayerEndpoint = LoggingMiddleware(log.With(logger, "method", "Layer"))(ep.LayerEndpoint)
+
+	//create the pause endpoints
+	ep.PauseEndpoint = MakePauseEndpoint(svc)
+	ep.PauseEndpoint = LoggingMiddleware(log.With(logger, "method", "Pause"))(ep.PauseEndpoint)
+	ep.ResumeEndpoint = MakeResumeEndpoint(svc)
+	ep.ResumeEndpoint = LoggingMiddleware(log.With(logger, "method", "Resume"))(ep.ResumeEndpoint)
+
+	//create the surrender, restart and retry endpoints
+	ep.SurrenderEndpoint = MakeSurrenderEndpoint(svc)
+	ep.SurrenderEndpoint = LoggingMiddleware(log.With(logger, "method", "Surrender"))(ep.SurrenderEndpoint)
+	ep.RestartEndpoint = MakeRestartEndpoint(svc)
+	ep.RestartEndpoint = LoggingMiddleware(log.With(logger, "method", "Restart"))(ep.RestartEndpoint)
+	ep.RetryEndpoint = MakeRetryEndpoint(svc)
+	ep.RetryEndpoint = LoggingMiddleware(log.With(logger, "method", "Retry"))(ep.RetryEndpoint)
 	return ep
 }
 
@@ -96,6 +215,28 @@ func MakeSaveGameEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
 	}
 }
 
+// MakeListGamesEndpoint returns an endpoint that invokes ListGames on the service.
+func MakeListGamesEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ListGamesRequest)
+		res, err := svc.ListGames(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ListGamesResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeDeleteGameEndpoint returns an endpoint that invokes DeleteGame on the service.
+func MakeDeleteGameEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(DeleteGameRequest)
+		err := svc.DeleteGame(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return DeleteGameResponse{Err: err}, nil
+	}
+}
+
 // MakeClickEndpoint returns an endpoint that invokes Click on the service.
 func MakeClickEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
@@ -107,6 +248,293 @@ func MakeClickEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
 	}
 }
 
+// MakeFlagEndpoint returns an endpoint that invokes Flag on the service.
+func MakeFlagEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(FlagRequest)
+		res, err := svc.Flag(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return FlagResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeChordEndpoint returns an endpoint that invokes Chord on the service.
+func MakeChordEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ChordRequest)
+		res, err := svc.Chord(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ChordResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeReplayEndpoint returns an endpoint that invokes Replay on the service.
+func MakeReplayEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ReplayRequest)
+		res, err := svc.Replay(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ReplayResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeExportLayoutEndpoint returns an endpoint that invokes ExportLayout on the service.
+func MakeExportLayoutEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ExportLayoutRequest)
+		res, err := svc.ExportLayout(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ExportLayoutResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeImageEndpoint returns an endpoint that loads the game to be drawn by the transport,
+// along with the options it should be drawn with.
+func MakeImageEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ImageRequest)
+		res, err := svc.LoadGame(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ImageResponse{Res: res, Options: req.Options, Err: err}, nil
+	}
+}
+
+// MakeHintEndpoint returns an endpoint that invokes Hint on the service.
+func MakeHintEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(HintRequest)
+		res, err := svc.Hint(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return HintResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeUndoEndpoint returns an endpoint that invokes Undo on the service.
+func MakeUndoEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(UndoRequest)
+		res, err := svc.Undo(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return UndoResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeCreateMatchEndpoint returns an endpoint that invokes CreateMatch on the service.
+func MakeCreateMatchEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(CreateMatchRequest)
+		res, err := svc.CreateMatch(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return CreateMatchResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeJoinMatchEndpoint returns an endpoint that invokes JoinMatch on the service.
+func MakeJoinMatchEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(JoinMatchRequest)
+		res, err := svc.JoinMatch(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return JoinMatchResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeStartMatchEndpoint returns an endpoint that invokes StartMatch on the service.
+func MakeStartMatchEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(StartMatchRequest)
+		res, err := svc.StartMatch(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return StartMatchResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeStandingsEndpoint returns an endpoint that invokes Standings on the service.
+func MakeStandingsEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(StandingsRequest)
+		res, err := svc.Standings(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return StandingsResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeJoinGameEndpoint returns an endpoint that invokes JoinGame on the service.
+func MakeJoinGameEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(JoinGameRequest)
+		res, err := svc.JoinGame(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return JoinGameResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeSubscribeEndpoint returns an endpoint that invokes Subscribe on the service.
+// The updates keep coming after the endpoint returns, until the context of the request is done
+func MakeSubscribeEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SubscribeRequest)
+		res, err := svc.Subscribe(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return SubscribeResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeScoresEndpoint returns an endpoint that invokes Scores on the service.
+func MakeScoresEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ScoresRequest)
+		res, err := svc.Scores(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ScoresResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeRatingEndpoint returns an endpoint that invokes Rating on the service.
+func MakeRatingEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RatingRequest)
+		res, err := svc.Rating(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return RatingResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeLadderEndpoint returns an endpoint that invokes Ladder on the service.
+func MakeLadderEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		res, err := svc.Ladder(ctx)
+
+		// wrap service response with endpoint response
+		return LadderResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeQueueEndpoint returns an endpoint that invokes Queue on the service.
+func MakeQueueEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(QueueRequest)
+		res, err := svc.Queue(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return QueueResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeTicketEndpoint returns an endpoint that invokes Ticket on the service.
+func MakeTicketEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(TicketRequest)
+		res, err := svc.Ticket(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return TicketResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeDailyEndpoint returns an endpoint that invokes Daily on the service.
+func MakeDailyEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(DailyRequest)
+		res, err := svc.Daily(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return DailyResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeLeaderboardEndpoint returns an endpoint that invokes DailyLeaderboard on the service.
+func MakeLeaderboardEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(LeaderboardRequest)
+		res, err := svc.DailyLeaderboard(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return LeaderboardResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeLayerEndpoint returns an endpoint that invokes Layer on the service.
+func MakeLayerEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(LayerRequest)
+		res, err := svc.Layer(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return LayerResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakePauseEndpoint returns an endpoint that invokes Pause on the service.
+func MakePauseEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(PauseRequest)
+		res, err := svc.Pause(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return PauseResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeResumeEndpoint returns an endpoint that invokes Resume on the service.
+func MakeResumeEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ResumeRequest)
+		res, err := svc.Resume(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return ResumeResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeSurrenderEndpoint returns an endpoint that invokes Surrender on the service.
+func MakeSurrenderEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SurrenderRequest)
+		res, err := svc.Surrender(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return SurrenderResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeRestartEndpoint returns an endpoint that invokes Restart on the service.
+func MakeRestartEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RestartRequest)
+		res, err := svc.Restart(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return RestartResponse{Res: res, Err: err}, nil
+	}
+}
+
+// MakeRetryEndpoint returns an endpoint that invokes Retry on the service.
+func MakeRetryEndpoint(svc service.Minesweepersvc) (ep endpoint.Endpoint) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RetryRequest)
+		res, err := svc.Retry(ctx, req.Req)
+
+		// wrap service response with endpoint response
+		return RetryResponse{Res: res, Err: err}, nil
+	}
+}
+
 // GetMinesweeperRequest is an empty request object
 // because no parameters are required to make this request
 type GetMinesweeperRequest struct{}
@@ -153,6 +581,29 @@ type SaveGameResponse struct {
 	Err error
 }
 
+// ListGamesRequest contains the query of the games to list
+type ListGamesRequest struct {
+	Req models.GameQuery
+}
+
+// ListGamesResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type ListGamesResponse struct {
+	Res *models.GamePage
+	Err error
+}
+
+// DeleteGameRequest contains the game to delete and the player deleting it
+type DeleteGameRequest struct {
+	Req models.DeleteGameRequest
+}
+
+// DeleteGameResponse tracks errors from the service
+type DeleteGameResponse struct {
+	Err error
+}
+
 // ClickRequest contains the data required for the endpoint
 type ClickRequest struct {
 	Req models.ClickRequest
@@ -165,3 +616,342 @@ type ClickResponse struct {
 	Res *models.Game
 	Err error
 }
+
+// FlagRequest contains the cell to flag
+type FlagRequest struct {
+	Req models.ClickRequest
+}
+
+// FlagResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type FlagResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// ChordRequest contains the clicked number to chord around
+type ChordRequest struct {
+	Req models.ClickRequest
+}
+
+// ChordResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type ChordResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// ReplayRequest contains the name of the game to replay
+type ReplayRequest struct {
+	Req string
+}
+
+// ReplayResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type ReplayResponse struct {
+	Res *models.Replay
+	Err error
+}
+
+// ExportLayoutRequest contains the name of the game to export
+type ExportLayoutRequest struct {
+	Req string
+}
+
+// ExportLayoutResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type ExportLayoutResponse struct {
+	Res models.Layout
+	Err error
+}
+
+// ImageRequest contains the name of the game to draw and how to draw it
+type ImageRequest struct {
+	Req     string
+	Options render.Options
+}
+
+// ImageResponse is the endpoint response
+// that wraps the game to draw and tracks
+// errors from Minesweeper Service
+type ImageResponse struct {
+	Res     *models.Game
+	Options render.Options
+	Err     error
+}
+
+// HintRequest contains the name of the game to give a hint for
+type HintRequest struct {
+	Req string
+}
+
+// HintResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type HintResponse struct {
+	Res *models.Hint
+	Err error
+}
+
+// UndoRequest contains the name of the game whose last click is taken back
+type UndoRequest struct {
+	Req string
+}
+
+// UndoResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type UndoResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// CreateMatchRequest contains the settings of the match to create
+type CreateMatchRequest struct {
+	Req *models.Match
+}
+
+// CreateMatchResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type CreateMatchResponse struct {
+	Res *models.Match
+	Err error
+}
+
+// JoinMatchRequest contains the match to join and the player joining it
+type JoinMatchRequest struct {
+	Req models.JoinRequest
+}
+
+// JoinMatchResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type JoinMatchResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// StartMatchRequest contains the name of the match to start
+type StartMatchRequest struct {
+	Req string
+}
+
+// StartMatchResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type StartMatchResponse struct {
+	Res *models.Match
+	Err error
+}
+
+// StandingsRequest contains the name of the match whose standings are requested
+type StandingsRequest struct {
+	Req string
+}
+
+// StandingsResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type StandingsResponse struct {
+	Res *models.Standings
+	Err error
+}
+
+// JoinGameRequest contains the shared game to join and the player joining it
+type JoinGameRequest struct {
+	Req models.JoinGameRequest
+}
+
+// JoinGameResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type JoinGameResponse struct {
+	Res *models.Session
+	Err error
+}
+
+// SubscribeRequest contains the name of the game to watch
+type SubscribeRequest struct {
+	Req string
+}
+
+// SubscribeResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type SubscribeResponse struct {
+	Res <-chan models.Update
+	Err error
+}
+
+// ScoresRequest contains the name of the flags game whose scores are requested
+type ScoresRequest struct {
+	Req string
+}
+
+// ScoresResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type ScoresResponse struct {
+	Res *models.Scoreboard
+	Err error
+}
+
+// RatingRequest contains the name of the player whose rating is requested
+type RatingRequest struct {
+	Req string
+}
+
+// RatingResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type RatingResponse struct {
+	Res *models.PlayerRating
+	Err error
+}
+
+// LadderRequest is an empty request object
+// because no parameters are required to make this request
+type LadderRequest struct{}
+
+// LadderResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type LadderResponse struct {
+	Res []models.PlayerRating
+	Err error
+}
+
+// QueueRequest contains the player joining the ranked queue
+type QueueRequest struct {
+	Req models.QueueRequest
+}
+
+// QueueResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type QueueResponse struct {
+	Res *models.Ticket
+	Err error
+}
+
+// TicketRequest contains the name of the player whose ticket is requested
+type TicketRequest struct {
+	Req string
+}
+
+// TicketResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type TicketResponse struct {
+	Res *models.Ticket
+	Err error
+}
+
+// DailyRequest contains the name of the player whose daily challenge game is requested
+type DailyRequest struct {
+	Req string
+}
+
+// DailyResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type DailyResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// LeaderboardRequest contains the day of the daily challenge whose leaderboard is requested, today when empty
+type LeaderboardRequest struct {
+	Req string
+}
+
+// LeaderboardResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type LeaderboardResponse struct {
+	Res *models.Leaderboard
+	Err error
+}
+
+// LayerRequest contains the game and the layer of its board requested
+type LayerRequest struct {
+	Req models.LayerRequest
+}
+
+// LayerResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type LayerResponse struct {
+	Res *models.Slice
+	Err error
+}
+
+// PauseRequest contains the name of the game to pause
+type PauseRequest struct {
+	Req string
+}
+
+// PauseResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type PauseResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// ResumeRequest contains the name of the game to resume
+type ResumeRequest struct {
+	Req string
+}
+
+// ResumeResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type ResumeResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// SurrenderRequest contains the name of the game to give up
+type SurrenderRequest struct {
+	Req string
+}
+
+// SurrenderResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type SurrenderResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// RestartRequest contains the name of the game to play again on a new board
+type RestartRequest struct {
+	Req string
+}
+
+// RestartResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type RestartResponse struct {
+	Res *models.Game
+	Err error
+}
+
+// RetryRequest contains the name of the game to play again on the same board
+type RetryRequest struct {
+	Req string
+}
+
+// RetryResponse is the endpoint response
+// that wraps the service response object and tracks
+// errors from Minesweeper Service
+type RetryResponse struct {
+	Res *models.Game
+	Err error
+}
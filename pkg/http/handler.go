@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-kit/kit/log"
@@ -13,6 +17,9 @@ import (
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/minesweeper/pkg/endpoints"
 	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/rawvf"
+	"github.com/minesweeper/pkg/render"
+	"github.com/minesweeper/pkg/service"
 )
 
 // NewHTTPHandler returns a handler that makes a set of endpoints available on
@@ -21,6 +28,12 @@ func NewHTTPHandler(endpoints endpoints.Endpoints, logger log.Logger) http.Handl
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerFinalizer(RequestLogFinalizer(logger)),
+		httptransport.ServerBefore(TokenToContext),
+	}
+	//the finalizer wraps the response writer in one that can't be flushed, so streams go without it
+	streamOptions := []httptransport.ServerOption{
+		httptransport.ServerErrorLogger(logger),
+		httptransport.ServerBefore(TokenToContext),
 	}
 
 	c := chi.NewRouter()
@@ -36,6 +49,18 @@ func NewHTTPHandler(endpoints endpoints.Endpoints, logger log.Logger) http.Handl
 		EncodeNewGameResponse,
 		append(options)...,
 	))
+	c.Method(http.MethodGet, "/minesweeper/games", httptransport.NewServer(
+		endpoints.ListGamesEndpoint,
+		DecodeListGamesRequest,
+		EncodeListGamesResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodDelete, "/minesweeper/games/{name}", httptransport.NewServer(
+		endpoints.DeleteGameEndpoint,
+		DecodeDeleteGameRequest,
+		EncodeDeleteGameResponse,
+		append(options)...,
+	))
 	c.Method(http.MethodGet, "/minesweeper/games/{name}", httptransport.NewServer(
 		endpoints.LoadGameEndpoint,
 		DecodeLoadGameRequest,
@@ -48,9 +73,187 @@ func NewHTTPHandler(endpoints endpoints.Endpoints, logger log.Logger) http.Handl
 		EncodeClickResponse,
 		append(options)...,
 	))
+	c.Method(http.MethodPut, "/minesweeper/games/{name}/flag", httptransport.NewServer(
+		endpoints.FlagEndpoint,
+		DecodeFlagRequest,
+		EncodeFlagResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPut, "/minesweeper/games/{name}/chord", httptransport.NewServer(
+		endpoints.ChordEndpoint,
+		DecodeChordRequest,
+		EncodeChordResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/replay", httptransport.NewServer(
+		endpoints.ReplayEndpoint,
+		DecodeReplayRequest,
+		EncodeReplayResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/replay.rawvf", httptransport.NewServer(
+		endpoints.ReplayEndpoint,
+		DecodeReplayRequest,
+		EncodeRAWVFResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/layout", httptransport.NewServer(
+		endpoints.ExportLayoutEndpoint,
+		DecodeExportLayoutRequest,
+		EncodeExportLayoutResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/image.png", httptransport.NewServer(
+		endpoints.ImageEndpoint,
+		DecodeImageRequest,
+		EncodePNGResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/image.svg", httptransport.NewServer(
+		endpoints.ImageEndpoint,
+		DecodeImageRequest,
+		EncodeSVGResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/hint", httptransport.NewServer(
+		endpoints.HintEndpoint,
+		DecodeHintRequest,
+		EncodeHintResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/undo", httptransport.NewServer(
+		endpoints.UndoEndpoint,
+		DecodeUndoRequest,
+		EncodeUndoResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/matches", httptransport.NewServer(
+		endpoints.CreateMatchEndpoint,
+		DecodeCreateMatchRequest,
+		EncodeCreateMatchResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/matches/{name}", httptransport.NewServer(
+		endpoints.StandingsEndpoint,
+		DecodeStandingsRequest,
+		EncodeStandingsResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/matches/{name}/players", httptransport.NewServer(
+		endpoints.JoinMatchEndpoint,
+		DecodeJoinMatchRequest,
+		EncodeJoinMatchResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/matches/{name}/start", httptransport.NewServer(
+		endpoints.StartMatchEndpoint,
+		DecodeStartMatchRequest,
+		EncodeStartMatchResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/players", httptransport.NewServer(
+		endpoints.JoinGameEndpoint,
+		DecodeJoinGameRequest,
+		EncodeJoinGameResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/events", httptransport.NewServer(
+		endpoints.SubscribeEndpoint,
+		DecodeSubscribeRequest,
+		EncodeSubscribeResponse,
+		append(streamOptions)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/scores", httptransport.NewServer(
+		endpoints.ScoresEndpoint,
+		DecodeScoresRequest,
+		EncodeScoresResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/ratings", httptransport.NewServer(
+		endpoints.LadderEndpoint,
+		DecodeLadderRequest,
+		EncodeLadderResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/ratings/{player}", httptransport.NewServer(
+		endpoints.RatingEndpoint,
+		DecodeRatingRequest,
+		EncodeRatingResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/queue", httptransport.NewServer(
+		endpoints.QueueEndpoint,
+		DecodeQueueRequest,
+		EncodeQueueResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/queue/{player}", httptransport.NewServer(
+		endpoints.TicketEndpoint,
+		DecodeTicketRequest,
+		EncodeTicketResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/daily", httptransport.NewServer(
+		endpoints.DailyEndpoint,
+		DecodeDailyRequest,
+		EncodeDailyResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/daily/leaderboard", httptransport.NewServer(
+		endpoints.LeaderboardEndpoint,
+		DecodeLeaderboardRequest,
+		EncodeLeaderboardResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodGet, "/minesweeper/games/{name}/layers/{layer}", httptransport.NewServer(
+		endpoints.LayerEndpoint,
+		DecodeLayerRequest,
+		EncodeLayerResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/pause", httptransport.NewServer(
+		endpoints.PauseEndpoint,
+		DecodePauseRequest,
+		EncodePauseResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/resume", httptransport.NewServer(
+		endpoints.ResumeEndpoint,
+		DecodeResumeRequest,
+		EncodeResumeResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/surrender", httptransport.NewServer(
+		endpoints.SurrenderEndpoint,
+		DecodeSurrenderRequest,
+		EncodeSurrenderResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/restart", httptransport.NewServer(
+		endpoints.RestartEndpoint,
+		DecodeRestartRequest,
+		EncodeRestartResponse,
+		append(options)...,
+	))
+	c.Method(http.MethodPost, "/minesweeper/games/{name}/retry", httptransport.NewServer(
+		endpoints.RetryEndpoint,
+		DecodeRetryRequest,
+		EncodeRetryResponse,
+		append(options)...,
+	))
 	return c
 }
 
+// TokenToContext moves the token of the player from the Authorization header to the context, for moves on shared games
+func TokenToContext(ctx context.Context, r *http.Request) context.Context {
+
+	token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if token == "" {
+		return ctx
+	}
+	return service.WithToken(ctx, token)
+}
+
 // RequestLogFinalizer is called at the end of an http request. Use it to log final
 // information regarding a request.
 func RequestLogFinalizer(logger log.Logger) httptransport.ServerFinalizerFunc {
@@ -144,6 +347,82 @@ func EncodeLoadGameResponse(_ context.Context, w http.ResponseWriter, response i
 	return json.NewEncoder(w).Encode(res.Res)
 }
 
+//DecodeListGamesRequest takes the query from the query parameters: "owner", "status", any of several separated by commas,
+//"from" and "to" as RFC 3339 times, "sort", "limit" and "cursor"
+func DecodeListGamesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	params := r.URL.Query()
+	query := models.GameQuery{
+		Owner:  params.Get("owner"),
+		Sort:   params.Get("sort"),
+		Cursor: params.Get("cursor"),
+	}
+	for _, s := range params["status"] {
+		for _, status := range strings.Split(s, ",") {
+			query.Status = append(query.Status, models.Status(status))
+		}
+	}
+	for key, t := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		if s := params.Get(key); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s", key)
+			}
+			*t = &parsed
+		}
+	}
+	if s := params.Get("limit"); s != "" {
+		var err error
+		if query.Limit, err = strconv.Atoi(s); err != nil {
+			return nil, errors.New("Invalid limit")
+		}
+	}
+	return endpoints.ListGamesRequest{
+		Req: query,
+	}, nil
+}
+
+func EncodeListGamesResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ListGamesResponse)
+	if !ok {
+		return errors.New("Error encoding ListGames response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeDeleteGameRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	return endpoints.DeleteGameRequest{
+		Req: models.DeleteGameRequest{
+			Name:   chi.URLParam(r, "name"),
+			Player: r.URL.Query().Get("player"),
+		},
+	}, nil
+}
+
+func EncodeDeleteGameResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.DeleteGameResponse)
+	if !ok {
+		return errors.New("Error encoding DeleteGame response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return err
+}
+
 func DecodeClickRequest(_ context.Context, r *http.Request) (interface{}, error) {
 
 	var req models.ClickRequest
@@ -175,3 +454,735 @@ func EncodeClickResponse(_ context.Context, w http.ResponseWriter, response inte
 
 	return json.NewEncoder(w).Encode(res.Res)
 }
+
+func DecodeFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	var req models.ClickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Missing Body Content")
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Malformed Body Content")
+		} else {
+			return nil, err
+		}
+	}
+	//the game is the one of the path
+	req.Name = chi.URLParam(r, "name")
+	return endpoints.FlagRequest{
+		Req: req,
+	}, nil
+}
+
+func EncodeFlagResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.FlagResponse)
+	if !ok {
+		return errors.New("Error encoding Flag response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeChordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	var req models.ClickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Missing Body Content")
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Malformed Body Content")
+		} else {
+			return nil, err
+		}
+	}
+	//the game is the one of the path
+	req.Name = chi.URLParam(r, "name")
+	return endpoints.ChordRequest{
+		Req: req,
+	}, nil
+}
+
+func EncodeChordResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ChordResponse)
+	if !ok {
+		return errors.New("Error encoding Chord response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeReplayRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.ReplayRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeReplayResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ReplayResponse)
+	if !ok {
+		return errors.New("Error encoding Replay response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+//EncodeRAWVFResponse encodes the replay of a game as a RAWVF video, the format used by community replay tools
+func EncodeRAWVFResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ReplayResponse)
+	if !ok {
+		return errors.New("Error encoding Replay response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	//games still being played have their replay without a board, and a video can't do without it
+	if res.Res.Board == nil {
+		return models.StatusError{Operation: "export", Status: res.Res.Status}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	return rawvf.Encode(w, res.Res)
+}
+
+func DecodeExportLayoutRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.ExportLayoutRequest{
+		Req: name,
+	}, nil
+}
+
+//EncodeExportLayoutResponse writes the layout as plain text, ready to be sent back in the layout of a new game
+func EncodeExportLayoutResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ExportLayoutResponse)
+	if !ok {
+		return errors.New("Error encoding ExportLayout response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err = io.WriteString(w, string(res.Res))
+	return err
+}
+
+//DecodeImageRequest takes the cell size (in pixels) and the theme of the image from the "size" and "theme" query parameters
+func DecodeImageRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	var size int
+	if s := r.URL.Query().Get("size"); s != "" {
+		var err error
+		if size, err = strconv.Atoi(s); err != nil {
+			return nil, errors.New("Invalid size")
+		}
+	}
+	opts, err := render.NewOptions(size, r.URL.Query().Get("theme"))
+	if err != nil {
+		return nil, err
+	}
+
+	name := chi.URLParam(r, "name")
+	return endpoints.ImageRequest{
+		Req:     name,
+		Options: opts,
+	}, nil
+}
+
+func EncodePNGResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ImageResponse)
+	if !ok {
+		return errors.New("Error encoding Image response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	return render.PNG(w, res.Res, res.Options)
+}
+
+func EncodeSVGResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ImageResponse)
+	if !ok {
+		return errors.New("Error encoding Image response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	return render.SVG(w, res.Res, res.Options)
+}
+
+func DecodeHintRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.HintRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeHintResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.HintResponse)
+	if !ok {
+		return errors.New("Error encoding Hint response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeUndoRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.UndoRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeUndoResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.UndoResponse)
+	if !ok {
+		return errors.New("Error encoding Undo response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeCreateMatchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req models.Match
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Missing Body Content")
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Malformed Body Content")
+		} else {
+			return nil, err
+		}
+	}
+
+	return endpoints.CreateMatchRequest{
+		Req: &req,
+	}, nil
+}
+
+func EncodeCreateMatchResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.CreateMatchResponse)
+	if !ok {
+		return errors.New("Error encoding CreateMatch response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeJoinMatchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req models.JoinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Missing Body Content")
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Malformed Body Content")
+		} else {
+			return nil, err
+		}
+	}
+	req.Match = chi.URLParam(r, "name")
+
+	return endpoints.JoinMatchRequest{
+		Req: req,
+	}, nil
+}
+
+func EncodeJoinMatchResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.JoinMatchResponse)
+	if !ok {
+		return errors.New("Error encoding JoinMatch response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeStartMatchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.StartMatchRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeStartMatchResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.StartMatchResponse)
+	if !ok {
+		return errors.New("Error encoding StartMatch response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeStandingsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.StandingsRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeStandingsResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.StandingsResponse)
+	if !ok {
+		return errors.New("Error encoding Standings response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeJoinGameRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req models.JoinGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Missing Body Content")
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Malformed Body Content")
+		} else {
+			return nil, err
+		}
+	}
+	req.Name = chi.URLParam(r, "name")
+
+	return endpoints.JoinGameRequest{
+		Req: req,
+	}, nil
+}
+
+func EncodeJoinGameResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.JoinGameResponse)
+	if !ok {
+		return errors.New("Error encoding JoinGame response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeSubscribeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.SubscribeRequest{
+		Req: name,
+	}, nil
+}
+
+// EncodeSubscribeResponse streams the updates of a game as server-sent events, named after their action,
+// until the client goes away
+func EncodeSubscribeResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.SubscribeResponse)
+	if !ok {
+		return errors.New("Error encoding Subscribe response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("Streaming not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for update := range res.Res {
+		data, err := json.Marshal(update)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", update.Event.Action, data); err != nil {
+			//the client went away, and the stream was already answered
+			return nil
+		}
+		flusher.Flush()
+	}
+	return nil
+}
+
+func DecodeScoresRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.ScoresRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeScoresResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ScoresResponse)
+	if !ok {
+		return errors.New("Error encoding Scores response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeRatingRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	player := chi.URLParam(r, "player")
+	return endpoints.RatingRequest{
+		Req: player,
+	}, nil
+}
+
+func EncodeRatingResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.RatingResponse)
+	if !ok {
+		return errors.New("Error encoding Rating response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeLadderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.LadderRequest{}, nil
+}
+
+func EncodeLadderResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.LadderResponse)
+	if !ok {
+		return errors.New("Error encoding Ladder response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeQueueRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req models.QueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Missing Body Content")
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("Malformed Body Content")
+		} else {
+			return nil, err
+		}
+	}
+
+	return endpoints.QueueRequest{
+		Req: req,
+	}, nil
+}
+
+func EncodeQueueResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.QueueResponse)
+	if !ok {
+		return errors.New("Error encoding Queue response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeTicketRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	player := chi.URLParam(r, "player")
+	return endpoints.TicketRequest{
+		Req: player,
+	}, nil
+}
+
+func EncodeTicketResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.TicketResponse)
+	if !ok {
+		return errors.New("Error encoding Ticket response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeDailyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	return endpoints.DailyRequest{
+		Req: r.URL.Query().Get("player"),
+	}, nil
+}
+
+func EncodeDailyResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.DailyResponse)
+	if !ok {
+		return errors.New("Error encoding Daily response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeLeaderboardRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	return endpoints.LeaderboardRequest{
+		Req: r.URL.Query().Get("date"),
+	}, nil
+}
+
+func EncodeLeaderboardResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.LeaderboardResponse)
+	if !ok {
+		return errors.New("Error encoding Leaderboard response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeLayerRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	layer, err := strconv.Atoi(chi.URLParam(r, "layer"))
+	if err != nil {
+		return nil, errors.New("Invalid layer")
+	}
+	return endpoints.LayerRequest{
+		Req: models.LayerRequest{Name: chi.URLParam(r, "name"), Layer: layer},
+	}, nil
+}
+
+func EncodeLayerResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.LayerResponse)
+	if !ok {
+		return errors.New("Error encoding Layer response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodePauseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.PauseRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodePauseResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.PauseResponse)
+	if !ok {
+		return errors.New("Error encoding Pause response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeResumeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.ResumeRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeResumeResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.ResumeResponse)
+	if !ok {
+		return errors.New("Error encoding Resume response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeSurrenderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.SurrenderRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeSurrenderResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.SurrenderResponse)
+	if !ok {
+		return errors.New("Error encoding Surrender response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeRestartRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.RestartRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeRestartResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.RestartResponse)
+	if !ok {
+		return errors.New("Error encoding Restart response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
+
+func DecodeRetryRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	name := chi.URLParam(r, "name")
+	return endpoints.RetryRequest{
+		Req: name,
+	}, nil
+}
+
+func EncodeRetryResponse(_ context.Context, w http.ResponseWriter, response interface{}) (err error) {
+
+	// cast response to known type
+	res, ok := response.(endpoints.RetryResponse)
+	if !ok {
+		return errors.New("Error encoding Retry response")
+	}
+
+	if res.Err != nil {
+		return res.Err
+	}
+
+	return json.NewEncoder(w).Encode(res.Res)
+}
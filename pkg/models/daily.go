@@ -0,0 +1,17 @@
+package models
+
+//Leaderboard is the ranking of the daily challenge of a day
+type Leaderboard struct {
+	Date    string             `json:"date"`    //Day of the challenge, as YYYY-MM-DD in UTC
+	Preset  string             `json:"preset"`  //beginner, intermediate or expert
+	Entries []LeaderboardEntry `json:"entries"` //Players who cleared the board, the fastest first
+}
+
+//LeaderboardEntry is a player who cleared the board of a daily challenge
+type LeaderboardEntry struct {
+	Rank   int     `json:"rank"`   //Place of the player, from 1
+	Player string  `json:"player"` //Name of the player
+	Game   string  `json:"game"`   //Name of the game the player cleared
+	Time   float64 `json:"time"`   //Seconds from the first click to the last one
+	Clicks int     `json:"clicks"` //Clicks the player made
+}
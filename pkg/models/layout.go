@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	LayoutMine  = '*' //LayoutMine marks a cell with a mine in a board layout
+	LayoutEmpty = '.' //LayoutEmpty marks a cell without a mine in a board layout
+)
+
+//Layout is a board written as text: one line per row, LayoutMine for mines and LayoutEmpty for the rest of the cells.
+//In JSON it can be either that text or a grid, as an array of rows where each row is a string or an array of booleans (true for mines)
+type Layout string
+
+//UnmarshalJSON accepts the layout as a single string or as a grid of rows
+func (l *Layout) UnmarshalJSON(data []byte) error {
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = Layout(text)
+		return nil
+	}
+
+	var rows []string
+	if err := json.Unmarshal(data, &rows); err == nil {
+		*l = Layout(strings.Join(rows, "\n"))
+		return nil
+	}
+
+	var grid [][]bool
+	if err := json.Unmarshal(data, &grid); err != nil {
+		return errors.New("layout must be a string or a grid of rows")
+	}
+	rows = make([]string, len(grid))
+	for i, cells := range grid {
+		row := make([]byte, len(cells))
+		for j, mine := range cells {
+			row[j] = LayoutEmpty
+			if mine {
+				row[j] = LayoutMine
+			}
+		}
+		rows[i] = string(row)
+	}
+	*l = Layout(strings.Join(rows, "\n"))
+	return nil
+}
+
+//ParseLayout builds a board from its layout. Only mines are set, numbers are left for the caller to compute
+func ParseLayout(layout Layout) ([]CellRow, error) {
+
+	lines := strings.Split(strings.TrimRight(string(layout), "\r\n"), "\n")
+	width := len(strings.TrimRight(lines[0], "\r"))
+	board := make([]CellRow, len(lines))
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			return nil, fmt.Errorf("layout row %d is empty", i)
+		}
+		if len(line) != width {
+			return nil, fmt.Errorf("layout row %d has %d cells, expected %d", i, len(line), width)
+		}
+		board[i] = make(CellRow, len(line))
+		for j, c := range line {
+			switch c {
+			case LayoutMine:
+				board[i][j].Mine = true
+			case LayoutEmpty:
+			default:
+				return nil, fmt.Errorf("layout row %d has an invalid cell %q", i, c)
+			}
+		}
+	}
+	return board, nil
+}
+
+//FormatLayout writes the layout of a board, the opposite of ParseLayout
+func FormatLayout(board []CellRow) Layout {
+
+	var sb strings.Builder
+	for _, row := range board {
+		for _, cell := range row {
+			if cell.Mine {
+				sb.WriteByte(LayoutMine)
+			} else {
+				sb.WriteByte(LayoutEmpty)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return Layout(sb.String())
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	SortCreated = "created" //SortCreated sorts games by when they were created
+	SortName    = "name"    //SortName sorts games by their name
+)
+
+//GameQuery picks the games to list, and the order they are listed in. Filters left empty take every game
+type GameQuery struct {
+	Owner  string     `json:"owner,omitempty"`  //Player of the games: the player of single player games, or any of the players of shared games
+	Status []Status   `json:"status,omitempty"` //Statuses the games can be in
+	From   *time.Time `json:"from,omitempty"`   //Games created at this time or later
+	To     *time.Time `json:"to,omitempty"`     //Games created before this time
+	Sort   string     `json:"sort,omitempty"`   //created or name, the first one first. A leading - lists the last one first. -created unless told otherwise
+	Limit  int        `json:"limit,omitempty"`  //Most games in a page
+	Cursor string     `json:"cursor,omitempty"` //Where the page starts, as the page before it said. The first page when empty
+}
+
+//DeleteGameRequest names the game to delete, and the player deleting it
+type DeleteGameRequest struct {
+	Name   string `json:"name"`   //Name of the game
+	Player string `json:"player"` //Player of the game. Shared games are deleted by their player with the token they joined with
+}
+
+//GamePage is a page of the games a query lists. Games are listed without their board
+type GamePage struct {
+	Games []Game `json:"games"`          //Games of the page, in the order of the query
+	Next  string `json:"next,omitempty"` //Cursor of the next page, while there are games left
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/minesweeper/pkg/topology"
+)
+
+const (
+	MatchLobby    = "lobby"    //MatchLobby is a match waiting for its players
+	MatchStarted  = "started"  //MatchStarted is a match being played
+	MatchFinished = "finished" //MatchFinished is a match with a winner, or whose players all lost
+)
+
+//Match is a race between players, each on their own game but all with the same board. The first to clear their board wins
+type Match struct {
+	Name          string          `json:"name"`                    //Name acts as an identifier of the Match
+	Rows          int             `json:"rows"`                    //How many rows the boards have
+	Columns       int             `json:"columns"`                 //How many columns the boards have
+	Mines         int             `json:"mines"`                   //How many mines the boards have
+	Lives         int             `json:"lives"`                   //Mines each player can hit before losing
+	Topology      string          `json:"topology,omitempty"`      //Shape of the boards: plane, the default, torus or hex
+	Neighbourhood string          `json:"neighbourhood,omitempty"` //Which cells numbers count, as in games
+	Offsets       []topology.Cell `json:"offsets,omitempty"`       //Rows and columns from a cell to each of its neighbours, as in games
+	Players       int             `json:"players"`                 //How many players can join
+	Countdown     int             `json:"countdown"`               //Seconds from the start of the match until clicks are allowed
+	Status        string          `json:"status"`                  //lobby, started or finished
+	Start         *time.Time      `json:"start,omitempty"`         //When players can start clicking, the same for all of them
+	Winner        string          `json:"winner,omitempty"`        //Player who cleared their board first
+	Entries       []MatchEntry    `json:"entries"`                 //Players who joined, with the game each of them plays
+	Ranked        bool            `json:"ranked,omitempty"`        //Ranked matches change the ratings of their players once finished
+	Seed          int64           `json:"-"`                       //Seed the mines of every game of the match are placed with. Never sent, it would give the mines away
+}
+
+//MatchEntry is a player taking part in a match
+type MatchEntry struct {
+	Player string `json:"player"` //Name of the player
+	Game   string `json:"game"`   //Name of the game the player plays
+}
+
+//JoinRequest contains the player that wants to join a match
+type JoinRequest struct {
+	Match  string `json:"match"`  //Name of the match to join
+	Player string `json:"player"` //Name of the player joining
+}
+
+//Standing is the progress of a player in a match
+type Standing struct {
+	Player   string  `json:"player"`   //Name of the player
+	Game     string  `json:"game"`     //Name of the game the player plays
+	Revealed float64 `json:"revealed"` //Percent of the safe cells of the board already revealed
+	Alive    bool    `json:"alive"`    //Whether the player can still play
+	Status   Status  `json:"status"`   //Status of the game of the player
+}
+
+//Standings is a match along with the progress of every player, the winner first and then by how much of their board they revealed
+type Standings struct {
+	Match     *Match     `json:"match"`
+	Standings []Standing `json:"standings"`
+}
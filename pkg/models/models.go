@@ -1,15 +1,50 @@
 package models
 
+import (
+	"time"
+
+	"github.com/minesweeper/pkg/topology"
+)
+
 const (
 	ErrNoNameGame = "Game doesnt have a name."
 )
 
+const (
+	ActionCreate    = "create"    //ActionCreate is the first event of every game, when its board is generated
+	ActionClick     = "click"     //ActionClick is a click made by the player in one of the cells
+	ActionFlag      = "flag"      //ActionFlag is a flag put in, or taken from, one of the cells
+	ActionChord     = "chord"     //ActionChord clicks every hidden neighbour of a clicked number without a flag, once the number has as many flags around as it says
+	ActionHint      = "hint"      //ActionHint is a hint given to the player
+	ActionUndo      = "undo"      //ActionUndo takes back the last click of the player
+	ActionJoin      = "join"      //ActionJoin is a player joining a shared game
+	ActionTimeout   = "timeout"   //ActionTimeout is the clock of a timed game running out, at the deadline of the game
+	ActionPause     = "pause"     //ActionPause stops a game, clock included
+	ActionResume    = "resume"    //ActionResume restarts a paused game
+	ActionSurrender = "surrender" //ActionSurrender is the player giving the game up
+	ActionRestart   = "restart"   //ActionRestart is the game played again on a new board with the same settings, as the game of the event
+	ActionRetry     = "retry"     //ActionRetry is the game played again on the same board, as the game of the event
+)
+
+const (
+	ModeCoop  = "coop"  //ModeCoop is a game shared by several players, who all click on the same board
+	ModeFlags = "flags" //ModeFlags is a game of two players taking turns to find mines. Whoever finds more than half of them wins
+)
+
+const (
+	HintSafe  = "safe"  //HintSafe points to a cell that is certainly safe to click
+	HintMine  = "mine"  //HintMine points to a cell that certainly holds a mine
+	HintGuess = "guess" //HintGuess points to the cell least likely to hold a mine, when nothing is certain
+)
+
 //Cell defines the different states of a single Cell
 type Cell struct {
-	Clicked bool `json:"clicked"` //Clicked specifies wether the Cell has been clicked or "discovered"
-	Mine    bool `json:"mine"`    //Mine specifies if there's a mine in the cell or not
-	Flag    bool `json:"flag"`    //This field indicates if the user has marked this Cell with a Flag (for future implementations)
-	Number  int  `json:"number"`  //Number is the quantity of nearby mines this cell has.
+	Clicked bool `json:"clicked"`         //Clicked specifies wether the Cell has been clicked or "discovered"
+	Mine    bool `json:"mine"`            //Mine specifies if there's a mine in the cell or not
+	Mines   int  `json:"mines,omitempty"` //How many mines the cell holds, in games with several mines per cell
+	Flag    bool `json:"flag"`            //This field indicates if the user has marked this Cell with a Flag
+	Flags   int  `json:"flags,omitempty"` //How many flags the user put in the cell, in games with several mines per cell
+	Number  int  `json:"number"`          //Number is the quantity of nearby mines this cell has.
 }
 
 //CellRow is a slice of cells. One or more of these form a board
@@ -17,19 +52,172 @@ type CellRow []Cell
 
 //Game has the information necessary to create a new game
 type Game struct {
-	Name       string    `json:"name"`            //Name acts as an identifier of the Game
-	Rows       int       `json:"rows"`            //How many rows the board has
-	Columns    int       `json:"columns"`         //How many columns the board has
-	Board      []CellRow `json:"board,omitempty"` //This is the structure itself of the board, many rows of cells
-	Discovered int       `json:"discovered"`      //This is the amount of cells already discovered. Used to check if the status is victory or not
-	Mines      int       `json:"mines"`           //How many mines the board has
-	Status     string    `json:"status"`          //Status of the current game. In progress, Game Over, Victory.
+	Name          string             `json:"name"`                         //Name acts as an identifier of the Game
+	Rows          int                `json:"rows"`                         //How many rows the board has
+	Columns       int                `json:"columns"`                      //How many columns the board has
+	Board         []CellRow          `json:"board,omitempty"`              //This is the structure itself of the board, many rows of cells
+	Discovered    int                `json:"discovered"`                   //This is the amount of cells already discovered. Used to check if the status is victory or not
+	Mines         int                `json:"mines"`                        //How many mines the board has
+	Status        Status             `json:"status"`                       //Status of the current game: new, in_progress, paused, won, lost, abandoned or timed_out
+	Layout        Layout             `json:"layout,omitempty"`             //Optional layout of the mines. When set, the board is built from it instead of randomly
+	Hints         int                `json:"hints"`                        //How many hints the player asked for
+	Assisted      bool               `json:"assisted"`                     //Assisted games (with hints) aren't eligible for leaderboards
+	UndoLimit     int                `json:"undo_limit"`                   //How many clicks can be taken back. Games with undos are practice games
+	UndoPenalty   int                `json:"undo_penalty"`                 //Seconds added to the time of the game for each undo
+	Undos         int                `json:"undos"`                        //How many clicks have been taken back
+	Penalty       int                `json:"penalty"`                      //Seconds added to the time of the game by undos
+	Practice      bool               `json:"practice"`                     //Practice games (with undos) aren't eligible for leaderboards
+	Lives         int                `json:"lives"`                        //Mines the player can still hit. Hit mines are revealed and flagged, and the game is over with the last life
+	Match         string             `json:"match,omitempty"`              //Match the game is part of, if any
+	Player        string             `json:"player,omitempty"`             //Player of the game: its owner in single player games, the player of its seat in matches
+	Start         *time.Time         `json:"start,omitempty"`              //When the game can be clicked, in matches
+	Seed          int64              `json:"-"`                            //Seed the mines are placed with, so games can share a board. Random when 0
+	Mode          string             `json:"mode,omitempty"`               //coop or flags for games of several players, empty for single player games
+	Stats         []PlayerStats      `json:"stats,omitempty"`              //What each player did, in shared games
+	Tokens        map[string]string  `json:"-"`                            //Player of each token, in shared games. Never sent, tokens are how players prove who they are
+	Turn          string             `json:"turn,omitempty"`               //Player who clicks next, in flags games
+	Scores        []Score            `json:"scores,omitempty"`             //Mines found by each player, in flags games. There's one for each slot taken, in the order players joined
+	Winner        string             `json:"winner,omitempty"`             //Player who found more than half of the mines, in flags games
+	Ranked        bool               `json:"ranked,omitempty"`             //Ranked flags games change the ratings of their players once won
+	Daily         string             `json:"daily,omitempty"`              //Day of the daily challenge the game is, as YYYY-MM-DD
+	Topology      string             `json:"topology,omitempty"`           //Shape of the board: plane, the default, torus, hex or cube
+	Layers        int                `json:"layers,omitempty"`             //Layers of cube boards, each of Rows x Columns. The board holds the rows of every layer, one layer after another
+	Geometry      *topology.Geometry `json:"geometry,omitempty"`           //How the cells of the board are drawn, as its topology says
+	MaxMines      int                `json:"max_mines_per_cell,omitempty"` //Most mines a cell can hold. Numbers count every mine around a cell. 1 unless told otherwise
+	Neighbourhood string             `json:"neighbourhood,omitempty"`      //Which cells numbers count, on square boards: knight, cross, extended or custom. The eight cells around when empty
+	Offsets       []topology.Cell    `json:"offsets,omitempty"`            //Rows and columns from a cell to each of its neighbours, given with the custom neighbourhood and filled in for the others
+	TimeLimit     int                `json:"time_limit,omitempty"`         //Seconds the game can be played for, from its creation. The game ends with the timeout status once they are over
+	Deadline      *time.Time         `json:"deadline,omitempty"`           //When the clock of a timed game runs out
+	MaxClicks     int                `json:"max_clicks,omitempty"`         //Most clicks the game can take. The game is over when the last one doesn't win it
+	Clicks        int                `json:"clicks,omitempty"`             //Clicks taken, the ones taken back by undos left out
+	PausedAt      *time.Time         `json:"paused_at,omitempty"`          //When the game was paused, while it is
+	PauseTime     float64            `json:"pause_time,omitempty"`         //Seconds the game spent paused, which aren't part of its time
+	Origin        string             `json:"origin,omitempty"`             //First game of the attempts the game is part of, when it restarts or retries another
+	Previous      string             `json:"previous,omitempty"`           //Game the game restarts or retries
+	Next          string             `json:"next,omitempty"`               //Game that restarted or retried the game, once it was played again
+	Attempt       int                `json:"attempt,omitempty"`            //How many times the origin was played again up to the game, 1 for its first restart or retry
+	Created       time.Time          `json:"created"`                      //When the game was created
+}
+
+//Score is how many mines a player found in a flags game
+type Score struct {
+	Player string `json:"player"` //Name of the player
+	Mines  int    `json:"mines"`  //Mines the player found
+}
+
+//Scoreboard is the state of a flags game without its board
+type Scoreboard struct {
+	Turn      string  `json:"turn"`             //Player who clicks next
+	Scores    []Score `json:"scores"`           //Mines found by each player
+	MinesLeft int     `json:"mines_left"`       //Mines nobody found yet
+	Winner    string  `json:"winner,omitempty"` //Player who found more than half of the mines
+}
+
+//PlayerStats is the contribution of a player to a shared game
+type PlayerStats struct {
+	Player   string `json:"player"`    //Name of the player
+	Clicks   int    `json:"clicks"`    //How many cells the player clicked
+	Revealed int    `json:"revealed"`  //How many safe cells the clicks of the player revealed
+	MinesHit int    `json:"mines_hit"` //How many mines the player clicked
 }
 
 //ClickRequest constains the information related to one "movement" or "action" taken by the player.
 //In this case, its a click in one of the cells.
 type ClickRequest struct {
-	Name   string `json:"name"`   //Name acts as an identifier of the Game
-	Row    int    `json:"row"`    //Which row is the player clicking
-	Column int    `json:"column"` //Which column is the player clicking
+	Name   string `json:"name"`            //Name acts as an identifier of the Game
+	Layer  int    `json:"layer,omitempty"` //Which layer is the player clicking, in cube boards
+	Row    int    `json:"row"`             //Which row is the player clicking
+	Column int    `json:"column"`          //Which column is the player clicking
+}
+
+//Event is a single timestamped action taken on a game.
+//Applying the events of a game in order over its initial board rebuilds its current state
+type Event struct {
+	Action string    `json:"action"`           //Which action was taken (create, click, flag, chord, hint, undo, join, timeout, pause, resume, surrender, restart, retry)
+	Time   time.Time `json:"time"`             //When the action was taken
+	Layer  int       `json:"layer,omitempty"`  //Layer of the cell the action was taken on, in cube boards
+	Row    int       `json:"row"`              //Row of the cell the action was taken on. Unused for create, hint and undo
+	Column int       `json:"column"`           //Column of the cell the action was taken on. Unused for create, hint and undo
+	Player string    `json:"player,omitempty"` //Player who took the action, in shared games and matches
+	Game   string    `json:"game,omitempty"`   //Game created to play the game again, for restart and retry
+}
+
+//JoinGameRequest contains the player that wants to join a shared game
+type JoinGameRequest struct {
+	Name   string `json:"name"`   //Name of the game to join
+	Player string `json:"player"` //Name of the player joining
+}
+
+//Session is what a player gets when joining a shared game: the token to send along with each move, as "Authorization: Bearer <token>"
+type Session struct {
+	Game   string `json:"game"`   //Name of the game
+	Player string `json:"player"` //Name of the player
+	Token  string `json:"token"`  //Secret token of the player
+}
+
+//Update is pushed to everyone watching a game each time it changes
+type Update struct {
+	Event Event `json:"event"` //What changed the game, and who did it
+	Game  *Game `json:"game"`  //The game after the change
+}
+
+//Replay contains everything needed to replay a game step by step: its settings, the board as it was generated and every event since
+type Replay struct {
+	Name          string          `json:"name"`                         //Name acts as an identifier of the Game
+	Rows          int             `json:"rows"`                         //How many rows the board has
+	Columns       int             `json:"columns"`                      //How many columns the board has
+	Mines         int             `json:"mines"`                        //How many mines the board has
+	UndoLimit     int             `json:"undo_limit,omitempty"`         //How many clicks can be taken back
+	UndoPenalty   int             `json:"undo_penalty,omitempty"`       //Seconds added to the time of the game for each undo
+	Lives         int             `json:"lives,omitempty"`              //Mines the player could hit when the game was created
+	Mode          string          `json:"mode,omitempty"`               //coop or flags for games of several players
+	Topology      string          `json:"topology,omitempty"`           //Shape of the board
+	Layers        int             `json:"layers,omitempty"`             //Layers of cube boards
+	MaxMines      int             `json:"max_mines_per_cell,omitempty"` //Most mines a cell can hold
+	Neighbourhood string          `json:"neighbourhood,omitempty"`      //Which cells numbers count
+	Offsets       []topology.Cell `json:"offsets,omitempty"`            //Rows and columns from a cell to each of its neighbours
+	TimeLimit     int             `json:"time_limit,omitempty"`         //Seconds the game could be played for, from its creation
+	MaxClicks     int             `json:"max_clicks,omitempty"`         //Most clicks the game could take
+	Origin        string          `json:"origin,omitempty"`             //First game of the attempts the game is part of
+	Previous      string          `json:"previous,omitempty"`           //Game the game restarted or retried
+	Attempt       int             `json:"attempt,omitempty"`            //How many times the origin was played again up to the game
+	Board         []CellRow       `json:"board"`                        //The board as it was when the game was created, before any click. Left out until the game is over
+	Events        []Event         `json:"events"`                       //Every action taken on the game, oldest first
+	Status        Status          `json:"status"`                       //Status the game reached after its last event
+}
+
+//Hint suggests a move from what the player can see of the board
+type Hint struct {
+	Kind          string        `json:"kind"`                    //safe, mine or guess
+	Layer         int           `json:"layer,omitempty"`         //Layer of the suggested cell, in cube boards
+	Row           int           `json:"row"`                     //Row of the suggested cell
+	Column        int           `json:"column"`                  //Column of the suggested cell
+	Reason        string        `json:"reason"`                  //How the hint was deduced
+	Probabilities []Probability `json:"probabilities,omitempty"` //Chance of each hidden cell holding a mine, only for guesses
+	Exact         bool          `json:"exact,omitempty"`         //Whether the probabilities are exact or sampled, only for guesses
+}
+
+//Probability is the chance of a hidden cell holding a mine
+type Probability struct {
+	Layer  int     `json:"layer,omitempty"`
+	Row    int     `json:"row"`
+	Column int     `json:"column"`
+	Mine   float64 `json:"mine"`
+}
+
+//LayerRequest asks for a layer of a cube board
+type LayerRequest struct {
+	Name  string `json:"name"`  //Name of the game
+	Layer int    `json:"layer"` //Which layer, from 0
+}
+
+//Slice is a single layer of a cube board, to draw it as a flat board
+type Slice struct {
+	Name    string    `json:"name"`    //Name of the game
+	Layer   int       `json:"layer"`   //Which layer of the board this is
+	Layers  int       `json:"layers"`  //How many layers the board has
+	Rows    int       `json:"rows"`    //How many rows the layer has
+	Columns int       `json:"columns"` //How many columns the layer has
+	Status  Status    `json:"status"`  //Status of the game
+	Board   []CellRow `json:"board"`   //Cells of the layer. Numbers count the mines of the neighbouring layers too
 }
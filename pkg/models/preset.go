@@ -0,0 +1,27 @@
+package models
+
+//Preset is a classic board size
+type Preset struct {
+	Name    string //beginner, intermediate or expert
+	Rows    int
+	Columns int
+	Mines   int
+}
+
+//Presets are the classic board sizes, from the smallest
+var Presets = []Preset{
+	{"beginner", 9, 9, 10},
+	{"intermediate", 16, 16, 40},
+	{"expert", 16, 30, 99},
+}
+
+//PresetOf returns the preset with the given name
+func PresetOf(name string) (Preset, bool) {
+
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
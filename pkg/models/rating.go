@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	TicketWaiting = "waiting" //TicketWaiting is a player in the queue, waiting for an opponent
+	TicketMatched = "matched" //TicketMatched is a player who was paired, with the match to play
+)
+
+//PlayerRating is the Glicko-2 rating of a player, along with how it changed after each ranked match
+type PlayerRating struct {
+	Player     string         `json:"player"`            //Name of the player
+	Rating     float64        `json:"rating"`            //Skill of the player, 1500 for new players
+	Deviation  float64        `json:"deviation"`         //How unsure the rating is. It shrinks as the player plays
+	Volatility float64        `json:"volatility"`        //How erratic the results of the player are
+	Matches    int            `json:"matches"`           //Ranked matches played
+	History    []RatingChange `json:"history,omitempty"` //Rating after each ranked match, the oldest first
+}
+
+//RatingChange is the rating of a player after a ranked match
+type RatingChange struct {
+	Match     string    `json:"match"`     //Name of the match, or of the game for flags games
+	Time      time.Time `json:"time"`      //When the match was rated
+	Rating    float64   `json:"rating"`    //Rating after the match
+	Deviation float64   `json:"deviation"` //Deviation after the match
+	Change    float64   `json:"change"`    //Points won or lost in the match
+}
+
+//QueueRequest contains the player that wants to be paired for a ranked match
+type QueueRequest struct {
+	Player string `json:"player"` //Name of the player
+}
+
+//Ticket is a player in the ranked queue
+type Ticket struct {
+	Player string    `json:"player"`          //Name of the player
+	Rating float64   `json:"rating"`          //Rating of the player when queued
+	Joined time.Time `json:"joined"`          //When the player was queued
+	Status string    `json:"status"`          //waiting or matched
+	Match  string    `json:"match,omitempty"` //Match the player was paired in, already started
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//Status is where a game is in its life. A game only goes from one status to another as its transitions allow
+type Status string
+
+const (
+	StatusNew        Status = "new"         //StatusNew is a game no cell has been clicked on yet
+	StatusInProgress Status = "in_progress" //StatusInProgress is a game being played
+	StatusPaused     Status = "paused"      //StatusPaused is a game its player stopped for a while, clock included
+	StatusWon        Status = "won"         //StatusWon is a game whose safe cells were all discovered
+	StatusLost       Status = "lost"        //StatusLost is a game that hit a mine with its last life, or spent its last click without winning
+	StatusAbandoned  Status = "abandoned"   //StatusAbandoned is a game its player gave up
+	StatusTimedOut   Status = "timed_out"   //StatusTimedOut is a timed game whose clock ran out
+)
+
+//transitions are the statuses a game can go to from each status. A click can end a game right away, and games that are over
+//go nowhere else
+var transitions = map[Status][]Status{
+	StatusNew:        {StatusInProgress, StatusWon, StatusLost, StatusAbandoned, StatusTimedOut},
+	StatusInProgress: {StatusPaused, StatusWon, StatusLost, StatusAbandoned, StatusTimedOut},
+	StatusPaused:     {StatusInProgress, StatusAbandoned},
+}
+
+//undos are the statuses an undo can take a game back to from each status: the ones it was in before a click, even a fatal one
+var undos = map[Status][]Status{
+	StatusInProgress: {StatusNew},
+	StatusLost:       {StatusNew, StatusInProgress},
+}
+
+//CanBecome tells whether a game in this status can go to another. Staying in the same status is always allowed
+func (s Status) CanBecome(to Status) bool {
+
+	if s == to {
+		return true
+	}
+	return among(to, transitions[s])
+}
+
+//CanUndoTo tells whether an undo can take a game in this status back to another
+func (s Status) CanUndoTo(to Status) bool {
+	return s == to || among(to, undos[s])
+}
+
+func among(s Status, statuses []Status) bool {
+
+	for _, status := range statuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+//Over tells whether a game in this status has ended, whatever the outcome
+func (s Status) Over() bool {
+	return s == StatusWon || s == StatusLost || s == StatusAbandoned || s == StatusTimedOut
+}
+
+//Known tells whether a status is one of the statuses a game can be in
+func (s Status) Known() bool {
+
+	switch s {
+	case StatusNew, StatusInProgress, StatusPaused, StatusWon, StatusLost, StatusAbandoned, StatusTimedOut:
+		return true
+	}
+	return false
+}
+
+//StatusError is an action a game can't take in its status, or a status it can't go to.
+//The HTTP API answers it with 409 Conflict, since the request is fine but the game isn't in a state to take it
+type StatusError struct {
+	Operation string //Action tried on the game, if any
+	To        Status //Status the game was meant to go to, if any
+	Status    Status //Status of the game when it was tried
+}
+
+func (e StatusError) Error() string {
+
+	if e.Operation == "" {
+		return fmt.Sprintf("A %s game can't become %s", words(e.Status), words(e.To))
+	}
+	return fmt.Sprintf("Can't %s a %s game", e.Operation, words(e.Status))
+}
+
+//StatusCode is the HTTP status errors of this kind are answered with
+func (e StatusError) StatusCode() int {
+	return http.StatusConflict
+}
+
+//words writes a status for messages
+func words(s Status) string {
+	return strings.Replace(string(s), "_", " ", -1)
+}
@@ -0,0 +1,107 @@
+//Package rating rates the skill of players with Glicko-2 (http://www.glicko.net/glicko/glicko2.pdf).
+//Besides a rating, every player has a deviation, how unsure the rating is, and a volatility, how erratic their results are.
+//Ratings are kept in the usual Glicko scale, where new players start at 1500
+package rating
+
+import "math"
+
+const (
+	DefaultRating     = 1500 //DefaultRating is the rating of a player who hasn't played yet
+	DefaultDeviation  = 350  //DefaultDeviation is the deviation of a player who hasn't played yet
+	DefaultVolatility = 0.06 //DefaultVolatility is the volatility of a player who hasn't played yet
+
+	tau     = 0.5      //Constrains how much the volatility changes over time
+	scale   = 173.7178 //Converts the Glicko scale into the Glicko-2 one
+	epsilon = 0.000001 //Tolerance of the iteration that finds the new volatility
+)
+
+//Rating is the skill of a player
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+}
+
+//Result is the outcome of a game against an opponent, rated as the opponent was before the game
+type Result struct {
+	Opponent Rating
+	Score    float64 //1 for a win, 0.5 for a draw and 0 for a loss
+}
+
+//New returns the rating of a player who hasn't played yet
+func New() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+//Update returns the rating after a rating period with the given results. A period without results only makes the rating less certain
+func (r Rating) Update(results []Result) Rating {
+
+	mu := (r.Rating - DefaultRating) / scale
+	phi := r.Deviation / scale
+	if len(results) == 0 {
+		r.Deviation = math.Sqrt(phi*phi+r.Volatility*r.Volatility) * scale
+		return r
+	}
+
+	//estimated variance of the rating, and improvement over it, based on the results only
+	var v, delta float64
+	for _, res := range results {
+		muj := (res.Opponent.Rating - DefaultRating) / scale
+		phij := res.Opponent.Deviation / scale
+		e := expected(mu, muj, phij)
+		v += g(phij) * g(phij) * e * (1 - e)
+		delta += g(phij) * (res.Score - e)
+	}
+	v = 1 / v
+	delta *= v
+
+	sigma := volatility(phi, r.Volatility, v, delta)
+	star := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(star*star)+1/v)
+	mu += phi * phi * delta / v
+
+	return Rating{Rating: mu*scale + DefaultRating, Deviation: phi * scale, Volatility: sigma}
+}
+
+//volatility finds the new volatility with the Illinois algorithm, as step 5 of the paper describes
+func volatility(phi, sigma, v, delta float64) float64 {
+
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		return ex*(delta*delta-phi*phi-v-ex)/(2*(phi*phi+v+ex)*(phi*phi+v+ex)) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+//expected is the score a player is expected to get against an opponent
+func expected(mu, muj, phij float64) float64 {
+	return 1 / (1 + math.Exp(-g(phij)*(mu-muj)))
+}
@@ -0,0 +1,30 @@
+package rating
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpdate(t *testing.T) {
+
+	Convey("Test Update", t, func() {
+		Convey("Example of the paper", func() {
+			player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+			r := player.Update([]Result{
+				{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: 1},
+				{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: 0},
+				{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: 0},
+			})
+			So(r.Rating, ShouldAlmostEqual, 1464.06, 0.01)
+			So(r.Deviation, ShouldAlmostEqual, 151.52, 0.01)
+			So(r.Volatility, ShouldAlmostEqual, 0.05999, 0.00001)
+		})
+		Convey("No games", func() {
+			r := New().Update(nil)
+			So(r.Rating, ShouldEqual, DefaultRating)
+			So(r.Deviation, ShouldBeGreaterThan, DefaultDeviation)
+		})
+	})
+
+}
@@ -0,0 +1,313 @@
+//Package rawvf converts game replays from and to the RAW Video Format (RAWVF), the plain text format
+//community tools like Arbiter and Viewer use to share minesweeper videos.
+//
+//A RAWVF file has three sections: a header of "Key: Value" lines, the board layout after "Board:"
+//(one line per row, '*' for mines and '0' for empty cells) and the mouse events after "Events:".
+//Each event line starts with its time in seconds since the first click, followed by the event name and,
+//for mouse events, the column and row of the cell (starting from 1) and the pixel position of the mouse.
+package rawvf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
+)
+
+const (
+	version    = "Rev5"
+	program    = "Minesweeper API"
+	squareSize = 16 //Pixels per cell, used to fill in the mouse position of events
+	timeLayout = "2006-01-02 15:04:05.000"
+)
+
+//mouse events of the format. Left clicks are clicks, right clicks flags and middle clicks chords
+const (
+	leftClick     = "lc"
+	leftRelease   = "lr"
+	rightClick    = "rc"
+	rightRelease  = "rr"
+	middleClick   = "mc"
+	middleRelease = "mr"
+	start         = "start"
+	won           = "won"
+	blast         = "blast"
+)
+
+//Encode writes a replay in RAWVF to w. Event times are relative to the first click, as RAWVF expects
+func Encode(w io.Writer, replay *models.Replay) error {
+
+	if len(replay.Board) != replay.Rows {
+		return errors.New("board doesnt match the replay rows")
+	}
+	if replay.Topology != "" && replay.Topology != topology.Plane {
+		return errors.New("RAWVF only has classic boards")
+	}
+	if len(replay.Offsets) > 0 {
+		return errors.New("RAWVF only has the classic neighbourhood")
+	}
+	if replay.MaxMines > 1 {
+		return errors.New("RAWVF has one mine per cell")
+	}
+
+	//RAWVF has no undos, so the clicks and chords taken back are left out of the video. An undo puts back the whole board
+	//from before the click, so the flags put after it go too
+	var moves []models.Event
+	for _, event := range replay.Events {
+		switch event.Action {
+		case models.ActionClick, models.ActionFlag, models.ActionChord:
+			moves = append(moves, event)
+		case models.ActionUndo:
+			for i := len(moves) - 1; i >= 0; i-- {
+				if moves[i].Action != models.ActionFlag {
+					moves = moves[:i]
+					break
+				}
+			}
+		}
+	}
+	var created, first time.Time
+	if len(replay.Events) > 0 {
+		created = replay.Events[0].Time
+		first = created
+	}
+	if len(moves) > 0 {
+		first = moves[0].Time
+	}
+	var total float64
+	if len(moves) > 0 {
+		total = moves[len(moves)-1].Time.Sub(first).Seconds()
+	}
+
+	bw := bufio.NewWriter(w)
+	header := [][2]string{
+		{"RawVF_Version", version},
+		{"Program", program},
+		{"Game", replay.Name},
+		{"Level", level(replay.Rows, replay.Columns, replay.Mines)},
+		{"Width", strconv.Itoa(replay.Columns)},
+		{"Height", strconv.Itoa(replay.Rows)},
+		{"Mines", strconv.Itoa(replay.Mines)},
+		{"Marks", "Off"},
+		{"Mode", "Classic"},
+		{"Time", fmt.Sprintf("%.2f", total)},
+		{"Timestamp", first.UTC().Format(timeLayout)},
+		{"Created", created.UTC().Format(timeLayout)},
+	}
+	for _, h := range header {
+		fmt.Fprintf(bw, "%s: %s\n", h[0], h[1])
+	}
+
+	fmt.Fprintln(bw, "Board:")
+	for i, row := range replay.Board {
+		if len(row) != replay.Columns {
+			return fmt.Errorf("board row %d doesnt match the replay columns", i)
+		}
+		for _, cell := range row {
+			if cell.Mine {
+				bw.WriteByte('*')
+			} else {
+				bw.WriteByte('0')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+
+	fmt.Fprintln(bw, "Events:")
+	for i, move := range moves {
+		at := move.Time.Sub(first).Seconds()
+		if i == 0 {
+			fmt.Fprintf(bw, "%.2f %s\n", at, start)
+		}
+		press, release := leftClick, leftRelease
+		switch move.Action {
+		case models.ActionFlag:
+			press, release = rightClick, rightRelease
+		case models.ActionChord:
+			press, release = middleClick, middleRelease
+		}
+		x, y := move.Column+1, move.Row+1
+		px, py := move.Column*squareSize+squareSize/2, move.Row*squareSize+squareSize/2
+		fmt.Fprintf(bw, "%.2f %s %d %d (%d %d)\n", at, press, x, y, px, py)
+		fmt.Fprintf(bw, "%.2f %s %d %d (%d %d)\n", at, release, x, y, px, py)
+	}
+	switch replay.Status {
+	case models.StatusWon:
+		fmt.Fprintf(bw, "%.2f %s\n", total, won)
+	case models.StatusLost:
+		fmt.Fprintf(bw, "%.2f %s\n", total, blast)
+	}
+
+	return bw.Flush()
+}
+
+//Decode parses a RAWVF video into a replay. Every left release on a cell becomes a click, every right release a flag
+//and every middle release a chord, so the game can be rebuilt with service.Rebuild. Other mouse events (presses, moves)
+//have no equivalent action in the service and are skipped.
+func Decode(r io.Reader) (*models.Replay, error) {
+
+	replay := &models.Replay{}
+	var first, created time.Time
+	section := "header"
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		switch line {
+		case "Board:":
+			section = "board"
+			continue
+		case "Events:":
+			section = "events"
+			continue
+		}
+
+		switch section {
+		case "header":
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("line %d: malformed header %q", n, line)
+			}
+			key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			var err error
+			switch key {
+			case "Game":
+				replay.Name = value
+			case "Width":
+				replay.Columns, err = strconv.Atoi(value)
+			case "Height":
+				replay.Rows, err = strconv.Atoi(value)
+			case "Mines":
+				replay.Mines, err = strconv.Atoi(value)
+			case "Timestamp":
+				//other programs write timestamps in their own layouts. Those are ignored and event times stay relative
+				if t, err := time.Parse(timeLayout, value); err == nil {
+					first = t
+				}
+			case "Created":
+				if t, err := time.Parse(timeLayout, value); err == nil {
+					created = t
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %v", n, key, err)
+			}
+
+		case "board":
+			row := make(models.CellRow, len(line))
+			for i, c := range line {
+				switch c {
+				case '*':
+					row[i].Mine = true
+				case '0':
+				default:
+					return nil, fmt.Errorf("line %d: invalid board cell %q", n, c)
+				}
+			}
+			replay.Board = append(replay.Board, row)
+
+		case "events":
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed event %q", n, line)
+			}
+			at, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid event time: %v", n, err)
+			}
+			switch fields[1] {
+			case leftRelease, rightRelease, middleRelease:
+				if len(fields) < 4 {
+					return nil, fmt.Errorf("line %d: malformed event %q", n, line)
+				}
+				x, errx := strconv.Atoi(fields[2])
+				y, erry := strconv.Atoi(fields[3])
+				if errx != nil || erry != nil {
+					return nil, fmt.Errorf("line %d: invalid event cell", n)
+				}
+				action := models.ActionClick
+				switch fields[1] {
+				case rightRelease:
+					action = models.ActionFlag
+				case middleRelease:
+					action = models.ActionChord
+				}
+				replay.Events = append(replay.Events, models.Event{
+					Action: action,
+					Time:   first.Add(time.Duration(at * float64(time.Second))),
+					Row:    y - 1,
+					Column: x - 1,
+				})
+			case won:
+				replay.Status = models.StatusWon
+			case blast:
+				replay.Status = models.StatusLost
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if replay.Rows == 0 || replay.Columns == 0 {
+		return nil, errors.New("missing board size")
+	}
+	if len(replay.Board) != replay.Rows {
+		return nil, errors.New("board doesnt match the video height")
+	}
+	for i, row := range replay.Board {
+		if len(row) != replay.Columns {
+			return nil, fmt.Errorf("board row %d doesnt match the video width", i)
+		}
+	}
+	setNumbers(replay.Board)
+
+	if created.IsZero() {
+		created = first
+	}
+	create := models.Event{Action: models.ActionCreate, Time: created}
+	replay.Events = append([]models.Event{create}, replay.Events...)
+	return replay, nil
+}
+
+//level returns the name RAWVF gives to the standard board sizes
+func level(rows, columns, mines int) string {
+
+	switch {
+	case rows == 8 && columns == 8 && mines == 10:
+		return "Beginner"
+	case rows == 16 && columns == 16 && mines == 40:
+		return "Intermediate"
+	case rows == 16 && columns == 30 && mines == 99:
+		return "Expert"
+	}
+	return "Custom"
+}
+
+//setNumbers counts the nearby mines of every cell, since RAWVF only stores where the mines are
+func setNumbers(board []models.CellRow) {
+
+	for i, row := range board {
+		for j := range row {
+			if !row[j].Mine {
+				continue
+			}
+			for x := i - 1; x < i+2; x++ {
+				for y := j - 1; y < j+2; y++ {
+					if x >= 0 && x < len(board) && y >= 0 && y < len(board[x]) && !(x == i && y == j) {
+						board[x][y].Number++
+					}
+				}
+			}
+		}
+	}
+}
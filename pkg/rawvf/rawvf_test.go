@@ -0,0 +1,106 @@
+package rawvf
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/service"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRoundTrip(t *testing.T) {
+
+	svc := service.NewBasicService(log.NewNopLogger())
+	//the replay of a game only has its board once the game is over, which the mine clicked last sees to
+	game := models.Game{
+		Name:   "video",
+		Layout: models.Layout("********\n**......\n" + strings.Repeat("........\n", 6)),
+	}
+	svc.NewGame(context.TODO(), &game)
+	for _, cell := range [][2]int{{7, 7}, {0, 0}} {
+		svc.Click(context.TODO(), models.ClickRequest{Name: "video", Row: cell[0], Column: cell[1]})
+	}
+	replay, _ := svc.Replay(context.TODO(), "video")
+
+	Convey("Test RAWVF", t, func() {
+		var buf bytes.Buffer
+		So(Encode(&buf, replay), ShouldBeNil)
+
+		Convey("Writes the header", func() {
+			So(buf.String(), ShouldContainSubstring, "Level: Beginner\n")
+			So(buf.String(), ShouldContainSubstring, "Width: 8\nHeight: 8\nMines: 10\n")
+		})
+		Convey("Decodes into the same game", func() {
+			decoded, err := Decode(&buf)
+			So(err, ShouldBeNil)
+			So(decoded.Board, ShouldResemble, replay.Board)
+			So(len(decoded.Events), ShouldEqual, len(replay.Events))
+
+			rebuilt, err := service.Rebuild(decoded)
+			So(err, ShouldBeNil)
+			stored, _ := svc.LoadGame(context.TODO(), "video")
+			So(rebuilt.Board, ShouldResemble, stored.Board)
+			So(rebuilt.Status, ShouldEqual, stored.Status)
+		})
+		Convey("Rejects a board of the wrong size", func() {
+			video := "Width: 3\nHeight: 2\nMines: 1\nBoard:\n*00\n00\nEvents:\n"
+			_, err := Decode(strings.NewReader(video))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestFlagsAndChords(t *testing.T) {
+
+	svc := service.NewBasicService(log.NewNopLogger())
+	game := models.Game{Name: "chords", Layout: "*...\n....\n....\n...*"}
+	svc.NewGame(context.TODO(), &game)
+	svc.Click(context.TODO(), models.ClickRequest{Name: "chords", Row: 1, Column: 1})
+	svc.Flag(context.TODO(), models.ClickRequest{Name: "chords", Row: 0, Column: 0})
+	svc.Chord(context.TODO(), models.ClickRequest{Name: "chords", Row: 1, Column: 1})
+	replay, _ := svc.Replay(context.TODO(), "chords")
+
+	Convey("Test RAWVF flags and chords", t, func() {
+		var buf bytes.Buffer
+		So(Encode(&buf, replay), ShouldBeNil)
+		So(buf.String(), ShouldContainSubstring, " rr 1 1 ")
+		So(buf.String(), ShouldContainSubstring, " mr 2 2 ")
+
+		decoded, err := Decode(&buf)
+		So(err, ShouldBeNil)
+		for i, event := range decoded.Events {
+			So(event.Action, ShouldEqual, replay.Events[i].Action)
+		}
+		rebuilt, err := service.Rebuild(decoded)
+		So(err, ShouldBeNil)
+		So(rebuilt.Status, ShouldEqual, models.StatusWon)
+		So(rebuilt.Board[0][0].Flag, ShouldBeTrue)
+	})
+}
+
+func TestUndos(t *testing.T) {
+
+	svc := service.NewBasicService(log.NewNopLogger())
+	game := models.Game{Name: "undone", Layout: "*...\n....\n....\n...*", UndoLimit: 1}
+	svc.NewGame(context.TODO(), &game)
+	svc.Flag(context.TODO(), models.ClickRequest{Name: "undone", Row: 0, Column: 0})
+	svc.Click(context.TODO(), models.ClickRequest{Name: "undone", Row: 1, Column: 1})
+	svc.Flag(context.TODO(), models.ClickRequest{Name: "undone", Row: 3, Column: 3})
+	svc.Undo(context.TODO(), "undone")
+	svc.Surrender(context.TODO(), "undone")
+	replay, _ := svc.Replay(context.TODO(), "undone")
+
+	Convey("Test RAWVF undos", t, func() {
+		var buf bytes.Buffer
+		So(Encode(&buf, replay), ShouldBeNil)
+		//the flag put before the click taken back stays, the one put after it goes with it
+		So(buf.String(), ShouldContainSubstring, " rr 1 1 ")
+		So(buf.String(), ShouldNotContainSubstring, " rr 4 4 ")
+		So(buf.String(), ShouldNotContainSubstring, " lr ")
+	})
+}
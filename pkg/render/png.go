@@ -0,0 +1,166 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+//digits is a 3x5 bitmap of each decimal digit, one row per string
+var digits = [10][5]string{
+	{"###", "#.#", "#.#", "#.#", "###"},
+	{".#.", "##.", ".#.", ".#.", "###"},
+	{"##.", "..#", ".#.", "#..", "###"},
+	{"##.", "..#", ".#.", "..#", "##."},
+	{"#.#", "#.#", "###", "..#", "..#"},
+	{"###", "#..", "##.", "..#", "##."},
+	{".##", "#..", "###", "#.#", "###"},
+	{"###", "..#", ".#.", ".#.", ".#."},
+	{"###", "#.#", "###", "#.#", "###"},
+	{"###", "#.#", "###", "..#", "##."},
+}
+
+//PNG draws the board of the game as a PNG image
+func PNG(w io.Writer, game *models.Game, opts Options) error {
+
+	l := layoutOf(game, opts.CellSize)
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	//hexagons don't cover the whole image, so the space around the board is left transparent
+	if l.cell(0, 0).outline == nil {
+		draw.Draw(img, img.Bounds(), image.NewUniform(opts.Theme.Grid), image.ZP, draw.Src)
+	}
+
+	for i, row := range game.Board {
+		for j, cell := range row {
+			shape := l.cell(i, j)
+			r := shape.box
+			switch lookOf(game, cell) {
+			case revealed:
+				fillCell(img, shape, opts.Theme.Revealed, opts.Theme.Grid)
+				if cell.Number > 0 {
+					drawNumber(img, r, cell.Number, opts.Theme.number(cell.Number))
+				}
+			case mine:
+				fillCell(img, shape, opts.Theme.Revealed, opts.Theme.Grid)
+				drawCircle(img, r, opts.Theme.Mine)
+			case flagged:
+				fillCell(img, shape, opts.Theme.Hidden, opts.Theme.Grid)
+				drawFlag(img, r, opts.Theme.Flag)
+			default:
+				fillCell(img, shape, opts.Theme.Hidden, opts.Theme.Grid)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, image.NewUniform(c), image.ZP, draw.Src)
+}
+
+//fillCell fills the shape of a cell. Hexagons get an edge of the grid colour around them, a pixel wide
+func fillCell(img *image.RGBA, shape cellShape, c color.RGBA, grid color.RGBA) {
+
+	if shape.outline == nil {
+		fill(img, shape.box, c)
+		return
+	}
+	r := bounds(shape.outline).Inset(-1)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			p := image.Pt(x, y)
+			switch {
+			case inside(p, shape.outline):
+				img.SetRGBA(x, y, c)
+			case near(p, shape.outline):
+				img.SetRGBA(x, y, grid)
+			}
+		}
+	}
+}
+
+//near tells whether a point outside a polygon touches it
+func near(p image.Point, polygon []image.Point) bool {
+
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if inside(p.Add(image.Pt(dx, dy)), polygon) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//drawNumber scales the bitmaps of the digits of the number to fill the middle of the cell. Numbers beyond 9,
+//which boards with cubes or several mines per cell can have, get their digits side by side with a gap between them
+func drawNumber(img *image.RGBA, r image.Rectangle, n int, c color.RGBA) {
+
+	text := strconv.Itoa(n)
+	width := 4*len(text) - 1
+	scale := r.Dy() / 7
+	if fit := r.Dx() / (width + 2); fit < scale {
+		scale = fit
+	}
+	if scale < 1 {
+		scale = 1
+	}
+	x0 := r.Min.X + (r.Dx()-width*scale)/2
+	y0 := r.Min.Y + (r.Dy()-5*scale)/2
+	for i, digit := range text {
+		for y, line := range digits[digit-'0'] {
+			for x, px := range line {
+				if px == '#' {
+					left := x0 + (4*i+x)*scale
+					fill(img, image.Rect(left, y0+y*scale, left+scale, y0+(y+1)*scale), c)
+				}
+			}
+		}
+	}
+}
+
+//drawCircle draws a filled circle in the middle of the cell, used for mines
+func drawCircle(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+
+	cx, cy := r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2
+	radius := r.Dx() / 3
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				img.SetRGBA(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+//drawFlag draws a pole with a triangular flag pointing left
+func drawFlag(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+
+	w, h := r.Dx(), r.Dy()
+	pole := r.Min.X + w*3/5
+	top, bottom := r.Min.Y+h/5, r.Min.Y+h*4/5
+	fill(img, image.Rect(pole, top, pole+1+w/16, bottom), c)
+	//the flag narrows from the pole to its tip
+	flagHeight := h * 2 / 5
+	for y := 0; y < flagHeight; y++ {
+		half := flagHeight / 2
+		dist := y - half
+		if dist < 0 {
+			dist = -dist
+		}
+		length := (half - dist) * (w * 2 / 5) / max(half, 1)
+		fill(img, image.Rect(pole-length, top+y, pole, top+y+1), c)
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
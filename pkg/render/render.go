@@ -0,0 +1,114 @@
+//Package render draws games as images, PNG or SVG, so they can be shared outside of the API.
+//Images follow what the player can see: hidden cells stay hidden, and mines are only drawn once the game is over
+package render
+
+import (
+	"errors"
+	"image/color"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const (
+	DefaultCellSize = 24      //DefaultCellSize is the side of each cell, in pixels, when no other is asked for
+	MinCellSize     = 8       //MinCellSize is the smallest cell that still fits its number
+	MaxCellSize     = 64      //MaxCellSize keeps images of big boards within a reasonable size
+	MaxPixels       = 1 << 22 //MaxPixels bounds the area of an image. Boards too big for it are drawn with smaller cells
+)
+
+//Theme is the set of colours used to draw a board
+type Theme struct {
+	Hidden   color.RGBA    //Background of cells that haven't been clicked
+	Revealed color.RGBA    //Background of clicked cells
+	Grid     color.RGBA    //Lines between cells
+	Mine     color.RGBA    //Mines, shown once the game is over
+	Flag     color.RGBA    //Flags
+	Numbers  [9]color.RGBA //Colour of each number, by the number itself. Index 0 is unused since empty cells show no number
+}
+
+//number returns the colour of a number. Boards with cubes or several mines per cell have numbers beyond 8, which take the colour of 8
+func (t Theme) number(n int) color.RGBA {
+
+	if n < len(t.Numbers) {
+		return t.Numbers[n]
+	}
+	return t.Numbers[len(t.Numbers)-1]
+}
+
+//Themes are the available themes by name
+var Themes = map[string]Theme{
+	"classic": {
+		Hidden:   color.RGBA{0xc0, 0xc0, 0xc0, 0xff},
+		Revealed: color.RGBA{0xe8, 0xe8, 0xe8, 0xff},
+		Grid:     color.RGBA{0x80, 0x80, 0x80, 0xff},
+		Mine:     color.RGBA{0x00, 0x00, 0x00, 0xff},
+		Flag:     color.RGBA{0xff, 0x00, 0x00, 0xff},
+		Numbers: [9]color.RGBA{
+			{}, {0x00, 0x00, 0xff, 0xff}, {0x00, 0x80, 0x00, 0xff}, {0xff, 0x00, 0x00, 0xff}, {0x00, 0x00, 0x80, 0xff},
+			{0x80, 0x00, 0x00, 0xff}, {0x00, 0x80, 0x80, 0xff}, {0x00, 0x00, 0x00, 0xff}, {0x80, 0x80, 0x80, 0xff},
+		},
+	},
+	"dark": {
+		Hidden:   color.RGBA{0x3a, 0x3f, 0x4b, 0xff},
+		Revealed: color.RGBA{0x1e, 0x21, 0x28, 0xff},
+		Grid:     color.RGBA{0x12, 0x14, 0x18, 0xff},
+		Mine:     color.RGBA{0xe0, 0x6c, 0x75, 0xff},
+		Flag:     color.RGBA{0xe5, 0xc0, 0x7b, 0xff},
+		Numbers: [9]color.RGBA{
+			{}, {0x61, 0xaf, 0xef, 0xff}, {0x98, 0xc3, 0x79, 0xff}, {0xe0, 0x6c, 0x75, 0xff}, {0xc6, 0x78, 0xdd, 0xff},
+			{0xd1, 0x9a, 0x66, 0xff}, {0x56, 0xb6, 0xc2, 0xff}, {0xab, 0xb2, 0xbf, 0xff}, {0x5c, 0x63, 0x70, 0xff},
+		},
+	},
+}
+
+//Options changes how a board is drawn
+type Options struct {
+	CellSize int   //Side of each cell, in pixels
+	Theme    Theme //Colours of the board
+}
+
+//NewOptions returns the options for a cell size and theme name. A zero size or empty theme take the defaults
+func NewOptions(cellSize int, theme string) (Options, error) {
+
+	if cellSize == 0 {
+		cellSize = DefaultCellSize
+	}
+	if cellSize < MinCellSize || cellSize > MaxCellSize {
+		return Options{}, errors.New("cell size out of range")
+	}
+	if theme == "" {
+		theme = "classic"
+	}
+	t, ok := Themes[theme]
+	if !ok {
+		return Options{}, errors.New("unknown theme")
+	}
+	return Options{CellSize: cellSize, Theme: t}, nil
+}
+
+//what the player can see of a cell
+type look int
+
+const (
+	hidden look = iota
+	flagged
+	revealed
+	mine
+)
+
+//lookOf applies the visibility rules: clicked cells show their number, flags are shown on hidden cells,
+//and mines are only shown when the game is over (as flags on a victory, since every mine was avoided)
+func lookOf(game *models.Game, cell models.Cell) look {
+
+	switch {
+	case cell.Clicked && !cell.Mine:
+		return revealed
+	case cell.Mine && game.Status.Over() && game.Status != models.StatusWon:
+		return mine
+	case cell.Mine && game.Status == models.StatusWon:
+		return flagged
+	case cell.Flag:
+		return flagged
+	}
+	return hidden
+}
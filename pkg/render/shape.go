@@ -0,0 +1,95 @@
+package render
+
+import (
+	"image"
+	"math"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
+)
+
+//cellShape is where a cell is drawn: the square its content goes in and, for cells that aren't squares, their outline
+type cellShape struct {
+	box     image.Rectangle
+	outline []image.Point //nil for squares, which fill their box
+}
+
+//layout places the cells of a board in an image of the given cell size. It returns the size of the image
+//and the shape of each cell, with a pixel left between cells for the grid
+type layout struct {
+	width, height int
+	size          int //Side of the cells, smaller than the one asked for when the image would take more than MaxPixels
+	cell          func(row, column int) cellShape
+}
+
+//layoutOf lays out the board with the biggest cells up to the given size that keep the image within MaxPixels,
+//but never smaller than MinCellSize
+func layoutOf(game *models.Game, size int) layout {
+
+	l := place(game, size)
+	for l.width*l.height > MaxPixels && l.size > MinCellSize {
+		l = place(game, l.size-1)
+	}
+	return l
+}
+
+//place lays out the board with cells of the given size
+func place(game *models.Game, size int) layout {
+
+	//cube boards draw their layers one below the other, as the board holds them
+	if topology.Of(game.Topology).Geometry().Cell != "hexagon" {
+		return layout{
+			width:  game.Columns*size + 1,
+			height: len(game.Board)*size + 1,
+			size:   size,
+			cell: func(i, j int) cellShape {
+				return cellShape{box: image.Rect(j*size+1, i*size+1, (j+1)*size, (i+1)*size)}
+			},
+		}
+	}
+
+	//pointy-top hexagons as wide as the cell size. Rows overlap by a quarter of their height, and odd rows are shifted half a cell
+	height := float64(size) * 2 / math.Sqrt(3)
+	step := height * 3 / 4
+	l := layout{
+		width:  game.Columns*size + size/2 + 1,
+		height: int(math.Ceil(float64(game.Rows-1)*step+height)) + 1,
+		size:   size,
+	}
+	l.cell = func(i, j int) cellShape {
+		cx := float64(j*size) + float64(size)/2 + float64(i&1)*float64(size)/2
+		cy := float64(i)*step + height/2
+		shape := cellShape{
+			box: image.Rect(int(cx)-size/2+1, int(cy)-size/2+1, int(cx)+size/2, int(cy)+size/2),
+		}
+		radius := height/2 - 1
+		for k := 0; k < 6; k++ {
+			angle := math.Pi/3*float64(k) - math.Pi/2
+			shape.outline = append(shape.outline, image.Pt(int(math.Round(cx+radius*math.Cos(angle))), int(math.Round(cy+radius*math.Sin(angle)))))
+		}
+		return shape
+	}
+	return l
+}
+
+//inside tells whether a point is inside a convex polygon, whose points go clockwise on the screen
+func inside(p image.Point, polygon []image.Point) bool {
+
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if (b.X-a.X)*(p.Y-a.Y)-(b.Y-a.Y)*(p.X-a.X) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//bounds is the smallest rectangle holding a polygon
+func bounds(polygon []image.Point) image.Rectangle {
+
+	var r image.Rectangle
+	for _, p := range polygon {
+		r = r.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+	}
+	return r
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+//SVG draws the board of the game as an SVG image
+func SVG(w io.Writer, game *models.Game, opts Options) error {
+
+	l := layoutOf(game, opts.CellSize)
+	size := l.size
+	width, height := l.width, l.height
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	if l.cell(0, 0).outline == nil {
+		fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, hex(opts.Theme.Grid))
+	}
+	fmt.Fprintf(bw, `<g font-family="monospace" font-weight="bold" font-size="%d" text-anchor="middle" dominant-baseline="central">`+"\n", size*2/3)
+
+	for i, row := range game.Board {
+		for j, cell := range row {
+			shape := l.cell(i, j)
+			x, y := shape.box.Min.X, shape.box.Min.Y
+			cx, cy := x+size/2, y+size/2
+			switch lookOf(game, cell) {
+			case revealed:
+				drawCell(bw, shape, opts.Theme.Revealed, opts.Theme.Grid)
+				if cell.Number > 0 {
+					fmt.Fprintf(bw, `<text x="%d" y="%d" fill="%s"%s>%d</text>`+"\n", cx, cy, hex(opts.Theme.number(cell.Number)), squeeze(cell.Number, size), cell.Number)
+				}
+			case mine:
+				drawCell(bw, shape, opts.Theme.Revealed, opts.Theme.Grid)
+				fmt.Fprintf(bw, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", cx, cy, size/3, hex(opts.Theme.Mine))
+			case flagged:
+				drawCell(bw, shape, opts.Theme.Hidden, opts.Theme.Grid)
+				pole, top, bottom := x+size*3/5, y+size/5, y+size*4/5
+				fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d"/>`+"\n", pole, top, pole, bottom, hex(opts.Theme.Flag), max(size/16, 1))
+				fmt.Fprintf(bw, `<polygon points="%d,%d %d,%d %d,%d" fill="%s"/>`+"\n", pole, top, pole, top+size*2/5, pole-size*2/5, top+size/5, hex(opts.Theme.Flag))
+			default:
+				drawCell(bw, shape, opts.Theme.Hidden, opts.Theme.Grid)
+			}
+		}
+	}
+
+	fmt.Fprintln(bw, "</g>")
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+//drawCell draws the shape of a cell. Hexagons get an edge of the grid colour around them
+func drawCell(w io.Writer, shape cellShape, c color.RGBA, grid color.RGBA) {
+
+	if shape.outline == nil {
+		r := shape.box
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", r.Min.X, r.Min.Y, r.Dx(), r.Dy(), hex(c))
+		return
+	}
+	points := make([]string, len(shape.outline))
+	for i, p := range shape.outline {
+		points[i] = fmt.Sprintf("%d,%d", p.X, p.Y)
+	}
+	fmt.Fprintf(w, `<polygon points="%s" fill="%s" stroke="%s"/>`+"\n", strings.Join(points, " "), hex(c), hex(grid))
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+//squeeze narrows numbers of several digits to the width of their cell, so they don't spill over their neighbours
+func squeeze(n int, size int) string {
+
+	if n < 10 {
+		return ""
+	}
+	return fmt.Sprintf(` textLength="%d" lengthAdjust="spacingAndGlyphs"`, size*4/5)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const updateBuffer = 16 //Updates a watcher can fall behind before missing some
+
+//broker pushes the updates of games to whoever is watching them
+type broker struct {
+	mu       sync.Mutex
+	watchers map[string]map[chan models.Update]bool
+}
+
+func newBroker() *broker {
+	return &broker{watchers: make(map[string]map[chan models.Update]bool)}
+}
+
+//subscribe returns a channel with the updates of a game, which is closed once ctx is done or the game is deleted
+func (b *broker) subscribe(ctx context.Context, name string) <-chan models.Update {
+
+	ch := make(chan models.Update, updateBuffer)
+	b.mu.Lock()
+	if b.watchers[name] == nil {
+		b.watchers[name] = make(map[chan models.Update]bool)
+	}
+	b.watchers[name][ch] = true
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		//the channel was already closed if the game was deleted
+		if !b.watchers[name][ch] {
+			return
+		}
+		delete(b.watchers[name], ch)
+		if len(b.watchers[name]) == 0 {
+			delete(b.watchers, name)
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+//publish sends an update to every watcher of a game. Watchers too slow to keep up miss it, rather than holding up the move
+func (b *broker) publish(name string, update models.Update) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.watchers[name] {
+		select {
+		case ch <- update:
+		default:
+		}
+	}
+}
+
+//close ends the updates of a game that was deleted, closing the channels of its watchers
+func (b *broker) close(name string) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.watchers[name] {
+		close(ch)
+	}
+	delete(b.watchers, name)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+//expired tells whether the clock of a timed game ran out while it was still being played
+func expired(game *models.Game, now time.Time) bool {
+	return game.Deadline != nil && allow(game, models.ActionTimeout) == nil && !now.Before(*game.Deadline)
+}
+
+//checkClock ends a timed game whose clock ran out before anything else is done with it, so the operation is checked against
+//the status the game really is in. It's needed besides the schedule, which may not have ended the game yet when a move
+//arrives right at the deadline. The caller holds the lock of the game
+func (m minesweeper) checkClock(ctx context.Context, game *models.Game) error {
+
+	if !expired(game, time.Now()) {
+		return nil
+	}
+	return m.timeout(ctx, game)
+}
+
+//schedule ends a timed game once its clock runs out, even if no request arrives for it anymore
+func (m minesweeper) schedule(game *models.Game) {
+
+	if game.Deadline == nil {
+		return
+	}
+	name := game.Name
+	time.AfterFunc(time.Until(*game.Deadline), func() {
+		m.expire(name)
+	})
+}
+
+//expire ends a game whose clock ran out, if nothing ended it before. Timers can fire a moment early,
+//in which case the game is scheduled again
+func (m minesweeper) expire(name string) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.db.GetGame(name)
+	if err != nil || game.Deadline == nil || allow(game, models.ActionTimeout) != nil {
+		return
+	}
+	if !expired(game, time.Now()) {
+		m.schedule(game)
+		return
+	}
+	if err := m.timeout(context.Background(), game); err != nil {
+		m.logger.Log("method", "expire", "name", name, "error", err)
+	}
+}
+
+//timeout ends a game as its clock ran out, when it ran out. Live subscribers get the event like any other,
+//and the match of the game is updated as with any other loss. The caller holds the lock of the game
+func (m minesweeper) timeout(ctx context.Context, game *models.Game) error {
+
+	if err := transition(game, models.StatusTimedOut); err != nil {
+		return err
+	}
+	event := models.Event{Action: models.ActionTimeout, Time: *game.Deadline}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return err
+	}
+	m.publish(game, event)
+	if game.Match != "" {
+		return m.finishMatch(ctx, game)
+	}
+	return nil
+}
+
+//spend counts a click of a game, and ends the game when it was the last one the game allows without being won
+func spend(game *models.Game) error {
+
+	game.Clicks++
+	if game.MaxClicks > 0 && game.Clicks >= game.MaxClicks && !over(game) {
+		return transition(game, models.StatusLost)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+const maxCoopPlayers = 16
+
+//modePlayers is how many players can join a game, by its mode
+var modePlayers = map[string]int{
+	models.ModeCoop:  maxCoopPlayers,
+	models.ModeFlags: flagsPlayers,
+}
+
+//tokenKey is the key of the token of the player in the context
+type tokenKey struct{}
+
+//WithToken returns a context carrying the token of a player, which moves on shared games are made with
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+//TokenFrom returns the token of the player carried by the context, if any
+func TokenFrom(ctx context.Context) string {
+
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+//JoinGame adds a player to a game of several players, shared or flags, and returns the token the player has to make moves with
+func (m minesweeper) JoinGame(ctx context.Context, req models.JoinGameRequest) (res *models.Session, err error) {
+
+	if req.Player == "" {
+		return &models.Session{}, errors.New("Player doesnt have a name.")
+	}
+	unlock := m.db.LockGame(req.Name)
+	defer unlock()
+
+	game, err := m.load(req.Name)
+	if err != nil {
+		return &models.Session{}, err
+	}
+	slots, ok := modePlayers[game.Mode]
+	if !ok {
+		return &models.Session{}, errors.New("Only games of several players can be joined")
+	}
+	if err := allow(game, models.ActionJoin); err != nil {
+		return &models.Session{}, err
+	}
+	for _, stats := range game.Stats {
+		if stats.Player == req.Player {
+			return &models.Session{}, errors.New("Player already joined")
+		}
+	}
+	if len(game.Stats) >= slots {
+		return &models.Session{}, errors.New("Game is full")
+	}
+
+	u, err := uuid.NewV4()
+	if err != nil {
+		return &models.Session{}, err
+	}
+	if game.Tokens == nil {
+		game.Tokens = make(map[string]string)
+	}
+	game.Tokens[u.String()] = req.Player
+	join(game, req.Player)
+
+	event := models.Event{
+		Action: models.ActionJoin,
+		Time:   time.Now().UTC(),
+		Player: req.Player,
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Session{}, err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Session{}, err
+	}
+	m.publish(game, event)
+	return &models.Session{Game: game.Name, Player: req.Player, Token: u.String()}, nil
+}
+
+//Subscribe returns the updates of a game as they happen, until ctx is done
+func (m minesweeper) Subscribe(ctx context.Context, name string) (res <-chan models.Update, err error) {
+
+	if m.broker == nil {
+		return nil, errors.New("Updates aren't available")
+	}
+	if _, err := m.load(name); err != nil {
+		return nil, err
+	}
+	return m.broker.subscribe(ctx, name), nil
+}
+
+//player returns who is making a move on a game. In games of several players it's the owner of the token in the context,
+//and moves without the token of a player of the game are turned away
+func player(ctx context.Context, game *models.Game) (string, error) {
+
+	if _, ok := modePlayers[game.Mode]; !ok {
+		return game.Player, nil
+	}
+	token := TokenFrom(ctx)
+	if player, ok := game.Tokens[token]; ok && token != "" {
+		return player, nil
+	}
+	return "", errors.New("Unauthorized: join the game and make moves with its token")
+}
+
+//countClick adds a click to the stats of the player who made it, given the cells discovered before it and whether it was on a mine
+func countClick(game *models.Game, player string, discovered int, mine bool) {
+
+	for i := range game.Stats {
+		if game.Stats[i].Player != player {
+			continue
+		}
+		game.Stats[i].Clicks++
+		game.Stats[i].Revealed += game.Discovered - discovered
+		if mine {
+			game.Stats[i].MinesHit++
+		}
+	}
+}
+
+//publish pushes a change of a game to whoever is watching it
+func (m minesweeper) publish(game *models.Game, event models.Event) {
+
+	if m.broker != nil {
+		m.broker.publish(game.Name, models.Update{Event: event, Game: view(game)})
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const dateLayout = "2006-01-02"
+
+//dailyPrefix starts the names of daily challenge games, and only theirs
+const dailyPrefix = "daily-"
+
+//Daily returns the daily challenge game of a player, creating it on the first request of the UTC day.
+//Every player gets the same board for the day, and only one attempt: later requests return the same game
+func (m minesweeper) Daily(ctx context.Context, player string) (res *models.Game, err error) {
+
+	if player == "" {
+		return &models.Game{}, errors.New("Player doesnt have a name.")
+	}
+	if m.dailies == nil {
+		return &models.Game{}, errors.New("Daily challenges aren't available")
+	}
+	date := time.Now().UTC().Format(dateLayout)
+	name := fmt.Sprintf("%s%s-%s", dailyPrefix, date, player)
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+	if game, err := m.db.GetGame(name); err == nil {
+		if game.Daily != date {
+			return &models.Game{}, errors.New("Name already used")
+		}
+		return view(game), nil
+	}
+
+	seed, p := m.daily(date)
+	game := &models.Game{
+		Name:    name,
+		Rows:    p.Rows,
+		Columns: p.Columns,
+		Mines:   p.Mines,
+		Seed:    seed,
+		Player:  player,
+		Daily:   date,
+	}
+	if err := m.createGame(game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.dailies.AddDaily(date, name); err != nil {
+		return &models.Game{}, err
+	}
+	return view(game), nil
+}
+
+//DailyLeaderboard ranks the players who cleared the daily challenge of a day, today's when date is empty, by how long it took them.
+//Time spent paused doesn't count. Assisted and practice games aren't ranked
+func (m minesweeper) DailyLeaderboard(ctx context.Context, date string) (res *models.Leaderboard, err error) {
+
+	if m.dailies == nil {
+		return &models.Leaderboard{}, errors.New("Daily challenges aren't available")
+	}
+	if date == "" {
+		date = time.Now().UTC().Format(dateLayout)
+	}
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return &models.Leaderboard{}, errors.New("date must be YYYY-MM-DD")
+	}
+	_, p := m.daily(date)
+	res = &models.Leaderboard{Date: date, Preset: p.Name, Entries: []models.LeaderboardEntry{}}
+
+	for _, name := range m.dailies.GetDailies(date) {
+		game, err := m.load(name)
+		if err != nil {
+			return &models.Leaderboard{}, err
+		}
+		if game.Status != models.StatusWon || game.Assisted || game.Practice {
+			continue
+		}
+		replay, err := m.db.GetReplay(name)
+		if err != nil {
+			return &models.Leaderboard{}, err
+		}
+		entry := models.LeaderboardEntry{Player: game.Player, Game: game.Name}
+		var first, last time.Time
+		for _, event := range replay.Events {
+			//a chord is a click too, and can be the one that clears the board
+			if event.Action != models.ActionClick && event.Action != models.ActionChord {
+				continue
+			}
+			if entry.Clicks == 0 {
+				first = event.Time
+			}
+			last = event.Time
+			entry.Clicks++
+		}
+		entry.Time = last.Sub(first).Seconds() - game.PauseTime + float64(game.Penalty)
+		res.Entries = append(res.Entries, entry)
+	}
+
+	sort.SliceStable(res.Entries, func(i, j int) bool {
+		return res.Entries[i].Time < res.Entries[j].Time
+	})
+	for i := range res.Entries {
+		res.Entries[i].Rank = i + 1
+	}
+	return res, nil
+}
+
+//daily derives the seed and the preset, one of the classic board sizes, of the daily challenge of a day from the date and the secret of the service.
+//Every player, and every instance sharing the secret, gets the same board, but the board can't be worked out from the date alone
+func (m minesweeper) daily(date string) (int64, models.Preset) {
+
+	h := fnv.New64a()
+	h.Write(m.secret)
+	h.Write([]byte("daily/" + date))
+	sum := h.Sum64()
+	seed := int64(sum >> 1)
+	if seed == 0 {
+		seed = 1
+	}
+	return seed, models.Presets[sum%uint64(len(models.Presets))]
+}
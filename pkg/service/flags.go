@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const (
+	flagsPlayers = 2
+
+	//flags games are played on the board of the original game, an odd number of mines so there are no draws
+	defaultFlagsRows    = 16
+	defaultFlagsColumns = 16
+	defaultFlagsMines   = 51
+)
+
+//flagsClick clicks a cell in a flags game. A mine is a point for the player, who keeps the turn, and a safe cell passes the turn.
+//The game is won by the first player to find more than half of the mines
+func flagsClick(game *models.Game, player string, row int, column int) error {
+
+	if err := checkCell(game, row, column); err != nil {
+		return err
+	}
+	if !game.Board[row][column].Mine {
+		revealCell(game, row, column)
+		game.Turn = opponent(game, player)
+		return nil
+	}
+
+	game.Board[row][column].Clicked = true
+	game.Board[row][column].Flag = true
+	for i := range game.Scores {
+		if game.Scores[i].Player != player {
+			continue
+		}
+		game.Scores[i].Mines++
+		if 2*game.Scores[i].Mines > game.Mines {
+			game.Winner = player
+			return transition(game, models.StatusWon)
+		}
+	}
+	return nil
+}
+
+//yourTurn checks that a player can click a flags game
+func yourTurn(game *models.Game, player string) error {
+
+	if len(game.Scores) < flagsPlayers {
+		return errors.New("Waiting for another player to join")
+	}
+	if game.Turn != player {
+		return errors.New("Not your turn")
+	}
+	return nil
+}
+
+//opponent returns the other player of a flags game
+func opponent(game *models.Game, player string) string {
+
+	for _, score := range game.Scores {
+		if score.Player != player {
+			return score.Player
+		}
+	}
+	return player
+}
+
+//join takes a player into a game of several players. In flags games the player gets a slot, and the first one starts
+func join(game *models.Game, player string) {
+
+	game.Stats = append(game.Stats, models.PlayerStats{Player: player})
+	if game.Mode == models.ModeFlags {
+		game.Scores = append(game.Scores, models.Score{Player: player})
+		if game.Turn == "" {
+			game.Turn = player
+		}
+	}
+}
+
+//Scores returns the turn and scores of a flags game, for players waiting for their turn
+func (m minesweeper) Scores(ctx context.Context, name string) (res *models.Scoreboard, err error) {
+
+	game, err := m.load(name)
+	if err != nil {
+		return &models.Scoreboard{}, err
+	}
+	if game.Mode != models.ModeFlags {
+		return &models.Scoreboard{}, errors.New("Only flags games have scores")
+	}
+	res = &models.Scoreboard{
+		Turn:      game.Turn,
+		Scores:    append([]models.Score{}, game.Scores...),
+		MinesLeft: game.Mines,
+		Winner:    game.Winner,
+	}
+	for _, score := range game.Scores {
+		res.MinesLeft -= score.Mines
+	}
+	return res, nil
+}
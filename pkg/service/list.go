@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const (
+	defaultPageSize = 20  //Games in a page when the query doesn't say
+	maxPageSize     = 100 //Most games a page can hold
+)
+
+//ListGames lists the games a query picks, a page at a time. Pages follow each other through the cursor of the page before,
+//so games created or deleted meanwhile don't shift the pages still to come
+func (m minesweeper) ListGames(ctx context.Context, query models.GameQuery) (res *models.GamePage, err error) {
+
+	for _, status := range query.Status {
+		if !status.Known() {
+			return &models.GamePage{}, fmt.Errorf("unknown status %q", status)
+		}
+	}
+	if query.Sort == "" {
+		query.Sort = "-" + models.SortCreated
+	}
+	if field := strings.TrimPrefix(query.Sort, "-"); field != models.SortCreated && field != models.SortName {
+		return &models.GamePage{}, errors.New("games are sorted by created or name")
+	}
+	if query.Limit < 0 {
+		return &models.GamePage{}, errors.New("limit can't be negative")
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultPageSize
+	}
+	if query.Limit > maxPageSize {
+		query.Limit = maxPageSize
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return &models.GamePage{}, errors.New("from must be before to")
+	}
+	return m.db.ListGames(query)
+}
+
+//DeleteGame removes a game for good, replay included. Only the player of a game can delete it, and games without a player,
+//which anyone can play, anyone can delete. Games of matches and daily challenges are part of their standings, and ranked
+//games of the ratings of their players, so they are kept. Games played again are kept while the game that played them
+//again is there, and deleting the last game of the chain lets the one before it be played again
+func (m minesweeper) DeleteGame(ctx context.Context, req models.DeleteGameRequest) (err error) {
+
+	unlock := m.db.LockGame(req.Name)
+	defer unlock()
+
+	game, err := m.db.GetGame(req.Name)
+	if err != nil {
+		return err
+	}
+	if err := owner(ctx, game, req.Player); err != nil {
+		return err
+	}
+	if game.Match != "" || game.Daily != "" || game.Ranked {
+		return errors.New("Games of matches, daily challenges and ranked games can't be deleted")
+	}
+	if game.Next != "" {
+		return fmt.Errorf("Game was played again as %s, which has to be deleted first", game.Next)
+	}
+	if game.Previous != "" {
+		//games are locked from the last one of the chain back, as deleting is the only thing that locks two of them
+		unlockPrevious := m.db.LockGame(game.Previous)
+		defer unlockPrevious()
+		previous, err := m.db.GetGame(game.Previous)
+		if err == nil && previous.Next == game.Name {
+			previous.Next = ""
+			if err := m.SaveGame(ctx, previous); err != nil {
+				return err
+			}
+		}
+	}
+	if err := m.db.DeleteGame(req.Name); err != nil {
+		return err
+	}
+	//whoever watches the game stops getting updates
+	if m.broker != nil {
+		m.broker.close(req.Name)
+	}
+	return nil
+}
+
+//owner tells whether a player is the one of the game. Players of shared games prove who they are with their token
+func owner(ctx context.Context, game *models.Game, name string) error {
+
+	if game.Player == "" {
+		return nil
+	}
+	if _, ok := modePlayers[game.Mode]; ok {
+		var err error
+		if name, err = player(ctx, game); err != nil {
+			return err
+		}
+	}
+	if name != game.Player {
+		return errors.New("Unauthorized: only the player of the game can delete it")
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const (
+	defaultPlayers   = 2
+	maxPlayers       = 16
+	defaultCountdown = 5 //Seconds from the start of a match until clicks are allowed
+)
+
+//CreateMatch creates a match in its lobby, along with a game for each of its players. All of them have the same mines.
+//Games are named after the match and the seat of their player, such as "final-1", and can't be clicked until the match starts
+func (m minesweeper) CreateMatch(ctx context.Context, match *models.Match) (res *models.Match, err error) {
+
+	if match.Name == "" {
+		return &models.Match{}, errors.New("Match doesnt have a name.")
+	}
+	if match.Players == 0 {
+		match.Players = defaultPlayers
+	}
+	if match.Players < 2 || match.Players > maxPlayers {
+		return &models.Match{}, fmt.Errorf("a match has between 2 and %d players", maxPlayers)
+	}
+	if match.Countdown == 0 {
+		match.Countdown = defaultCountdown
+	}
+	if match.Countdown < 0 {
+		return &models.Match{}, errors.New("countdown can't be negative")
+	}
+	if _, err := m.matches.GetMatch(match.Name); err == nil {
+		return &models.Match{}, errors.New("Match name already used")
+	}
+	match.Seed = time.Now().UnixNano()
+	match.Status = models.MatchLobby
+	match.Start = nil
+	match.Winner = ""
+	match.Entries = nil
+
+	for seat := 1; seat <= match.Players; seat++ {
+		game := &models.Game{
+			Name:          seatGame(match.Name, seat),
+			Rows:          match.Rows,
+			Columns:       match.Columns,
+			Mines:         match.Mines,
+			Lives:         match.Lives,
+			Topology:      match.Topology,
+			Neighbourhood: match.Neighbourhood,
+			Offsets:       match.Offsets,
+			Match:         match.Name,
+			Seed:          match.Seed,
+		}
+		if err := m.createGame(game); err != nil {
+			m.dropSeats(match.Name, seat-1)
+			return &models.Match{}, err
+		}
+		//the match shows the defaults its games took
+		match.Rows, match.Columns, match.Mines, match.Lives = game.Rows, game.Columns, game.Mines, game.Lives
+		match.Neighbourhood, match.Offsets = game.Neighbourhood, game.Offsets
+	}
+	if err := m.matches.InsertMatch(match); err != nil {
+		m.dropSeats(match.Name, match.Players)
+		return &models.Match{}, err
+	}
+	return m.matches.GetMatch(match.Name)
+}
+
+//dropSeats deletes the games of the first seats of a match that couldn't be created, so they aren't left without their match
+func (m minesweeper) dropSeats(match string, seats int) {
+
+	for seat := 1; seat <= seats; seat++ {
+		if err := m.db.DeleteGame(seatGame(match, seat)); err != nil {
+			m.logger.Log("method", "CreateMatch", "match", match, "error", err)
+		}
+	}
+}
+
+//JoinMatch takes the next free seat of a match in its lobby, and returns the game of the seat
+func (m minesweeper) JoinMatch(ctx context.Context, req models.JoinRequest) (res *models.Game, err error) {
+
+	if req.Player == "" {
+		return &models.Game{}, errors.New("Player doesnt have a name.")
+	}
+	var entry models.MatchEntry
+	err = m.matches.UpdateMatch(req.Match, func(match *models.Match) error {
+		if match.Status != models.MatchLobby {
+			return errors.New("Match already started")
+		}
+		for _, e := range match.Entries {
+			if e.Player == req.Player {
+				return errors.New("Player already joined")
+			}
+		}
+		if len(match.Entries) >= match.Players {
+			return errors.New("Match is full")
+		}
+		entry = models.MatchEntry{Player: req.Player, Game: seatGame(match.Name, len(match.Entries)+1)}
+		match.Entries = append(match.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return &models.Game{}, err
+	}
+
+	unlock := m.db.LockGame(entry.Game)
+	defer unlock()
+	game, err := m.load(entry.Game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	game.Player = entry.Player
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	return view(game), nil
+}
+
+//StartMatch closes the lobby of a match and sets when its players can start clicking, after the countdown of the match.
+//Every player gets the same start time, and clicks before it are rejected
+func (m minesweeper) StartMatch(ctx context.Context, name string) (res *models.Match, err error) {
+
+	var start time.Time
+	var entries []models.MatchEntry
+	err = m.matches.UpdateMatch(name, func(match *models.Match) error {
+		if match.Status != models.MatchLobby {
+			return errors.New("Match already started")
+		}
+		if len(match.Entries) < 2 {
+			return errors.New("A match needs at least 2 players")
+		}
+		start = time.Now().UTC().Add(time.Duration(match.Countdown) * time.Second)
+		match.Start = &start
+		match.Status = models.MatchStarted
+		entries = match.Entries
+		return nil
+	})
+	if err != nil {
+		return &models.Match{}, err
+	}
+
+	for _, entry := range entries {
+		if err := m.startGame(ctx, entry.Game, start); err != nil {
+			return &models.Match{}, err
+		}
+	}
+	return m.matches.GetMatch(name)
+}
+
+//startGame sets when a game of a match can start being clicked
+func (m minesweeper) startGame(ctx context.Context, name string, start time.Time) error {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+	game, err := m.load(name)
+	if err != nil {
+		return err
+	}
+	game.Start = &start
+	return m.SaveGame(ctx, game)
+}
+
+//Standings returns a match along with the progress of each of its players: how much of their board they revealed and whether they are still alive
+func (m minesweeper) Standings(ctx context.Context, name string) (res *models.Standings, err error) {
+
+	match, err := m.matches.GetMatch(name)
+	if err != nil {
+		return &models.Standings{}, err
+	}
+	res = &models.Standings{Match: match, Standings: []models.Standing{}}
+	for _, entry := range match.Entries {
+		game, err := m.load(entry.Game)
+		if err != nil {
+			return &models.Standings{}, err
+		}
+		standing := models.Standing{
+			Player: entry.Player,
+			Game:   entry.Game,
+			Alive:  !lost(game),
+			Status: game.Status,
+		}
+		if safe := safeCells(game); safe > 0 {
+			standing.Revealed = 100 * float64(game.Discovered) / float64(safe)
+		}
+		res.Standings = append(res.Standings, standing)
+	}
+
+	sort.SliceStable(res.Standings, func(i, j int) bool {
+		a, b := res.Standings[i], res.Standings[j]
+		if (a.Player == match.Winner) != (b.Player == match.Winner) {
+			return a.Player == match.Winner
+		}
+		if a.Alive != b.Alive {
+			return a.Alive
+		}
+		return a.Revealed > b.Revealed
+	})
+	return res, nil
+}
+
+//finishMatch updates the match of a game that just ended. The first victory wins the match,
+//and a match whose players all lost is finished without a winner. Ranked matches are rated once finished
+func (m minesweeper) finishMatch(ctx context.Context, game *models.Game) error {
+
+	var ranked bool
+	if game.Status == models.StatusWon {
+		err := m.matches.UpdateMatch(game.Match, func(match *models.Match) error {
+			if match.Winner == "" {
+				match.Winner = game.Player
+				match.Status = models.MatchFinished
+				ranked = match.Ranked
+			}
+			return nil
+		})
+		if err != nil || !ranked {
+			return err
+		}
+		return m.rateMatch(ctx, game.Match)
+	}
+
+	match, err := m.matches.GetMatch(game.Match)
+	if err != nil {
+		return err
+	}
+	for _, entry := range match.Entries {
+		other, err := m.load(entry.Game)
+		if err != nil {
+			return err
+		}
+		if !lost(other) {
+			return nil
+		}
+	}
+	err = m.matches.UpdateMatch(game.Match, func(match *models.Match) error {
+		if match.Status == models.MatchStarted {
+			match.Status = models.MatchFinished
+			ranked = match.Ranked
+		}
+		return nil
+	})
+	if err != nil || !ranked {
+		return err
+	}
+	return m.rateMatch(ctx, game.Match)
+}
+
+//started checks that the match of a game has started, so the game can be clicked
+func started(game *models.Game) error {
+
+	if game.Start == nil {
+		return errors.New("Match hasn't started")
+	}
+	if time.Now().Before(*game.Start) {
+		return fmt.Errorf("Match starts at %s", game.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+//seatGame is the name of the game of a seat in a match
+func seatGame(match string, seat int) string {
+	return fmt.Sprintf("%s-%d", match, seat)
+}
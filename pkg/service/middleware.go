@@ -81,6 +81,37 @@ func (mw loggingMiddleware) SaveGame(ctx context.Context, game *models.Game) (er
 	return mw.next.SaveGame(ctx, game)
 }
 
+func (mw loggingMiddleware) ListGames(ctx context.Context, query models.GameQuery) (res *models.GamePage, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "ListGames",
+			"owner", query.Owner,
+			"sort", query.Sort,
+			"cursor", query.Cursor,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.ListGames(ctx, query)
+}
+
+func (mw loggingMiddleware) DeleteGame(ctx context.Context, req models.DeleteGameRequest) (err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "DeleteGame",
+			"name", req.Name,
+			"player", req.Player,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.DeleteGame(ctx, req)
+}
+
 func (mw loggingMiddleware) Click(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
 
 	// defer logging to log response
@@ -95,3 +126,363 @@ func (mw loggingMiddleware) Click(ctx context.Context, req models.ClickRequest)
 	return mw.next.Click(ctx, req)
 
 }
+
+func (mw loggingMiddleware) Flag(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Flag",
+			"name", req.Name,
+			"row", req.Row,
+			"column", req.Column,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Flag(ctx, req)
+}
+
+func (mw loggingMiddleware) Chord(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Chord",
+			"name", req.Name,
+			"row", req.Row,
+			"column", req.Column,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Chord(ctx, req)
+}
+
+func (mw loggingMiddleware) Replay(ctx context.Context, name string) (res *models.Replay, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Replay",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Replay(ctx, name)
+}
+
+func (mw loggingMiddleware) ExportLayout(ctx context.Context, name string) (res models.Layout, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "ExportLayout",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.ExportLayout(ctx, name)
+}
+
+func (mw loggingMiddleware) Hint(ctx context.Context, name string) (res *models.Hint, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Hint",
+			"name", name,
+			"kind", res.Kind,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Hint(ctx, name)
+}
+
+func (mw loggingMiddleware) Undo(ctx context.Context, name string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Undo",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Undo(ctx, name)
+}
+
+func (mw loggingMiddleware) CreateMatch(ctx context.Context, match *models.Match) (res *models.Match, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "CreateMatch",
+			"name", match.Name,
+			"players", match.Players,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.CreateMatch(ctx, match)
+}
+
+func (mw loggingMiddleware) JoinMatch(ctx context.Context, req models.JoinRequest) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "JoinMatch",
+			"match", req.Match,
+			"player", req.Player,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.JoinMatch(ctx, req)
+}
+
+func (mw loggingMiddleware) StartMatch(ctx context.Context, name string) (res *models.Match, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "StartMatch",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.StartMatch(ctx, name)
+}
+
+func (mw loggingMiddleware) Standings(ctx context.Context, name string) (res *models.Standings, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Standings",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Standings(ctx, name)
+}
+
+func (mw loggingMiddleware) JoinGame(ctx context.Context, req models.JoinGameRequest) (res *models.Session, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "JoinGame",
+			"name", req.Name,
+			"player", req.Player,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.JoinGame(ctx, req)
+}
+
+func (mw loggingMiddleware) Subscribe(ctx context.Context, name string) (res <-chan models.Update, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Subscribe",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Subscribe(ctx, name)
+}
+
+func (mw loggingMiddleware) Scores(ctx context.Context, name string) (res *models.Scoreboard, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Scores",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Scores(ctx, name)
+}
+
+func (mw loggingMiddleware) Rating(ctx context.Context, player string) (res *models.PlayerRating, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Rating",
+			"player", player,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Rating(ctx, player)
+}
+
+func (mw loggingMiddleware) Ladder(ctx context.Context) (res []models.PlayerRating, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Ladder",
+			"players", len(res),
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Ladder(ctx)
+}
+
+func (mw loggingMiddleware) Queue(ctx context.Context, req models.QueueRequest) (res *models.Ticket, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Queue",
+			"player", req.Player,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Queue(ctx, req)
+}
+
+func (mw loggingMiddleware) Ticket(ctx context.Context, player string) (res *models.Ticket, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Ticket",
+			"player", player,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Ticket(ctx, player)
+}
+
+func (mw loggingMiddleware) Daily(ctx context.Context, player string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Daily",
+			"player", player,
+			"name", res.Name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Daily(ctx, player)
+}
+
+func (mw loggingMiddleware) DailyLeaderboard(ctx context.Context, date string) (res *models.Leaderboard, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "DailyLeaderboard",
+			"date", date,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.DailyLeaderboard(ctx, date)
+}
+
+func (mw loggingMiddleware) Layer(ctx context.Context, req models.LayerRequest) (res *models.Slice, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Layer",
+			"name", req.Name,
+			"layer", req.Layer,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Layer(ctx, req)
+}
+
+func (mw loggingMiddleware) Pause(ctx context.Context, name string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Pause",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Pause(ctx, name)
+}
+
+func (mw loggingMiddleware) Resume(ctx context.Context, name string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Resume",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Resume(ctx, name)
+}
+
+func (mw loggingMiddleware) Surrender(ctx context.Context, name string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Surrender",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Surrender(ctx, name)
+}
+
+func (mw loggingMiddleware) Restart(ctx context.Context, name string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Restart",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Restart(ctx, name)
+}
+
+func (mw loggingMiddleware) Retry(ctx context.Context, name string) (res *models.Game, err error) {
+
+	// defer logging to log response
+	defer func() {
+		mw.logger.Log("method", "Retry",
+			"name", name,
+			"error", err)
+	}()
+
+	// call Minesweepersvc to invoke
+	// next middleware (or service)
+	return mw.next.Retry(ctx, name)
+}
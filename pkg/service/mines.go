@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
+)
+
+const maxMinesPerCell = 3 //Most mines a single cell can hold, in games with several mines per cell
+
+//Flag puts a flag in a hidden cell, or takes it away. In games with several mines per cell a cell takes a flag for each of
+//its mines: every flag adds one, up to the most mines a cell can hold, and the next one takes them all away
+func (m minesweeper) Flag(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	unlock := m.db.LockGame(req.Name)
+	defer unlock()
+
+	game, err := m.load(req.Name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := allow(game, models.ActionFlag); err != nil {
+		return &models.Game{}, err
+	}
+	if game.Match != "" {
+		if err := started(game); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	if game.Mode == models.ModeFlags {
+		return &models.Game{}, errors.New("flags games flag the mines found on their own")
+	}
+	row, err := boardRow(game, req.Layer, req.Row)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := flagCell(game, row, req.Column); err != nil {
+		return &models.Game{}, err
+	}
+
+	//flags are recorded like clicks, so replays show them too
+	event := models.Event{
+		Action: models.ActionFlag,
+		Time:   time.Now().UTC(),
+		Layer:  req.Layer,
+		Row:    req.Row,
+		Column: req.Column,
+		Player: player,
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	m.publish(game, event)
+	return view(game), nil
+}
+
+//Chord clicks every hidden neighbour of a clicked number that has no flag, once the number has as many flags around it,
+//mines hit included, as it says. Wrong flags make the chord hit the mines they left out. The chord is a single click
+func (m minesweeper) Chord(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
+
+	unlock := m.db.LockGame(req.Name)
+	defer unlock()
+
+	game, err := m.load(req.Name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := allow(game, models.ActionChord); err != nil {
+		return &models.Game{}, err
+	}
+	if game.Match != "" {
+		if err := started(game); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	if game.Mode == models.ModeFlags {
+		return &models.Game{}, errors.New("flags games have no flags to chord with")
+	}
+	row, err := boardRow(game, req.Layer, req.Row)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	//chords are taken back by undos like clicks
+	var before *models.Game
+	if game.Undos < game.UndoLimit {
+		before = copyGame(game)
+	}
+	discovered, lives := game.Discovered, game.Lives
+	if err := chord(game, row, req.Column); err != nil {
+		return &models.Game{}, err
+	}
+	countClick(game, player, discovered, game.Lives < lives)
+	if before != nil {
+		if err := m.db.PushHistory(before, game.UndoLimit-game.Undos); err != nil {
+			return &models.Game{}, err
+		}
+	}
+
+	event := models.Event{
+		Action: models.ActionChord,
+		Time:   time.Now().UTC(),
+		Layer:  req.Layer,
+		Row:    req.Row,
+		Column: req.Column,
+		Player: player,
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	m.publish(game, event)
+	if game.Match != "" && over(game) {
+		if err := m.finishMatch(ctx, game); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	return view(game), nil
+}
+
+//chord clicks the hidden neighbours without a flag of a clicked number, once its flags match it
+func chord(game *models.Game, row int, column int) error {
+
+	if column > game.Columns-1 || column < 0 {
+		return errors.New("invalid column")
+	}
+	cell := game.Board[row][column]
+	if !cell.Clicked || cell.Number == 0 {
+		return errors.New("only clicked numbers can be chorded")
+	}
+	flags := 0
+	var hidden []topology.Cell
+	for _, n := range neighbours(game, row, column) {
+		flags += marked(game.Board[n.Row][n.Column])
+		if !game.Board[n.Row][n.Column].Clicked && marked(game.Board[n.Row][n.Column]) == 0 {
+			hidden = append(hidden, n)
+		}
+	}
+	if flags != cell.Number {
+		return fmt.Errorf("the cell needs %d flags around it, it has %d", cell.Number, flags)
+	}
+	for _, n := range hidden {
+		//earlier clicks of the chord may have already revealed the cell, or ended the game
+		if over(game) || game.Board[n.Row][n.Column].Clicked {
+			continue
+		}
+		if err := clickCell(game, n.Row, n.Column); err != nil {
+			return err
+		}
+	}
+	return spend(game)
+}
+
+//marked is how many mines of a cell the player knows of: those of a mine hit, or the flags put in a hidden cell
+func marked(cell models.Cell) int {
+
+	switch {
+	case cell.Clicked && cell.Mine:
+		return cellMines(cell)
+	case cell.Clicked:
+		return 0
+	case cell.Flags > 0:
+		return cell.Flags
+	case cell.Flag:
+		return 1
+	}
+	return 0
+}
+
+//flagCell changes the flags of a hidden cell
+func flagCell(game *models.Game, row int, column int) error {
+
+	if err := checkCell(game, row, column); err != nil {
+		return err
+	}
+	cell := &game.Board[row][column]
+	if most := maxMines(game); most > 1 {
+		cell.Flags = (cell.Flags + 1) % (most + 1)
+		cell.Flag = cell.Flags > 0
+		return nil
+	}
+	cell.Flag = !cell.Flag
+	return nil
+}
+
+//checkMaxMines checks how many mines the cells of a game can hold. Layouts and flags games have one mine per cell
+func checkMaxMines(game *models.Game) error {
+
+	if game.MaxMines == 0 {
+		game.MaxMines = 1
+	}
+	if game.MaxMines < 1 || game.MaxMines > maxMinesPerCell {
+		return fmt.Errorf("cells hold between 1 and %d mines", maxMinesPerCell)
+	}
+	if game.MaxMines > 1 && game.Layout != "" {
+		return errors.New("layouts have one mine per cell")
+	}
+	if game.MaxMines > 1 && game.Mode == models.ModeFlags {
+		return errors.New("flags games have one mine per cell")
+	}
+	return nil
+}
+
+//maxMines is the most mines a cell of a game can hold. Games from before the option hold one
+func maxMines(game *models.Game) int {
+
+	if game.MaxMines > 1 {
+		return game.MaxMines
+	}
+	return 1
+}
+
+//cellMines is how many mines a cell holds
+func cellMines(cell models.Cell) int {
+
+	if cell.Mines > 0 {
+		return cell.Mines
+	}
+	if cell.Mine {
+		return 1
+	}
+	return 0
+}
+
+//safeCells is how many cells of a game don't hold a mine. Those are the cells to discover to win,
+//since the mines of a game can be packed in fewer cells than there are mines
+func safeCells(game *models.Game) int {
+
+	safe := 0
+	for _, row := range game.Board {
+		for _, cell := range row {
+			if !cell.Mine {
+				safe++
+			}
+		}
+	}
+	return safe
+}
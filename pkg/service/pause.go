@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+const maxRankedPause = 120 //Seconds a ranked game can spend paused in total. Once they are spent, the game is resumed on its own
+
+//Pause stops a game for a while, clock included. Paused games are shown without their board, so nobody can study it off the clock.
+//Matches are a race against the clocks of the other players, so their games can't be paused
+func (m minesweeper) Pause(ctx context.Context, name string) (res *models.Game, err error) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.db.GetGame(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := allow(game, models.ActionPause); err != nil {
+		return &models.Game{}, err
+	}
+	if game.Match != "" {
+		return &models.Game{}, errors.New("Match games can't be paused")
+	}
+	if game.Ranked && game.PauseTime >= maxRankedPause {
+		return &models.Game{}, errors.New("No pause time left")
+	}
+
+	event := models.Event{Action: models.ActionPause, Time: time.Now().UTC(), Player: player}
+	if err := pause(game, event.Time); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if game.Ranked {
+		m.scheduleResume(game)
+	}
+	hide(game)
+	m.publish(game, event)
+	return view(game), nil
+}
+
+//Resume restarts the clock of a paused game, and shows its board again
+func (m minesweeper) Resume(ctx context.Context, name string) (res *models.Game, err error) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.db.GetGame(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.resume(ctx, game, models.Event{Action: models.ActionResume, Time: time.Now().UTC(), Player: player}); err != nil {
+		return &models.Game{}, err
+	}
+	return view(game), nil
+}
+
+//resume resumes a paused game with the given event. The caller holds the lock of the game
+func (m minesweeper) resume(ctx context.Context, game *models.Game, event models.Event) error {
+
+	if err := allow(game, models.ActionResume); err != nil {
+		return err
+	}
+	if err := resume(game, event.Time); err != nil {
+		return err
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return err
+	}
+	m.schedule(game)
+	m.publish(game, event)
+	return nil
+}
+
+//scheduleResume resumes a paused ranked game once it spends all the pause time ranked games have
+func (m minesweeper) scheduleResume(game *models.Game) {
+
+	name, paused := game.Name, *game.PausedAt
+	left := time.Duration((maxRankedPause - game.PauseTime) * float64(time.Second))
+	time.AfterFunc(left, func() {
+		unlock := m.db.LockGame(name)
+		defer unlock()
+
+		game, err := m.db.GetGame(name)
+		//the game may have been resumed, and even paused again, meanwhile
+		if err != nil || game.PausedAt == nil || !game.PausedAt.Equal(paused) {
+			return
+		}
+		event := models.Event{Action: models.ActionResume, Time: paused.Add(left)}
+		if err := m.resume(context.Background(), game, event); err != nil {
+			m.logger.Log("method", "scheduleResume", "name", name, "error", err)
+		}
+	})
+}
+
+//pause stops a game at the given time
+func pause(game *models.Game, at time.Time) error {
+
+	if err := transition(game, models.StatusPaused); err != nil {
+		return err
+	}
+	game.PausedAt = &at
+	return nil
+}
+
+//resume restarts a game paused until the given time. The pause is added to the pause time of the game,
+//and the deadline of timed games is put off by as long, since their clock was stopped meanwhile
+func resume(game *models.Game, at time.Time) error {
+
+	if err := transition(game, models.StatusInProgress); err != nil {
+		return err
+	}
+	paused := at.Sub(*game.PausedAt)
+	game.PauseTime += paused.Seconds()
+	game.PausedAt = nil
+	if game.Deadline != nil {
+		deadline := game.Deadline.Add(paused)
+		game.Deadline = &deadline
+	}
+	return nil
+}
+
+//hide leaves out the board of a paused game
+func hide(game *models.Game) {
+
+	if game.Status == models.StatusPaused {
+		game.Board = nil
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/rating"
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+const (
+	matchWindow  = 100 //Rating points apart two players of the queue can be to be paired, right after queueing
+	windowGrowth = 10  //Points the window grows for every second a player waits, so nobody waits forever
+)
+
+//Rating returns the rating of a player along with its history. Players who haven't played ranked matches have the rating of new players
+func (m minesweeper) Rating(ctx context.Context, player string) (res *models.PlayerRating, err error) {
+
+	if m.ratings == nil {
+		return &models.PlayerRating{}, errors.New("Ratings aren't available")
+	}
+	res, ok := m.ratings.GetRating(player)
+	if !ok {
+		r := rating.New()
+		res.Rating, res.Deviation, res.Volatility = r.Rating, r.Deviation, r.Volatility
+	}
+	return res, nil
+}
+
+//Ladder returns every rated player, the best first
+func (m minesweeper) Ladder(ctx context.Context) (res []models.PlayerRating, err error) {
+
+	if m.ratings == nil {
+		return nil, errors.New("Ratings aren't available")
+	}
+	res = []models.PlayerRating{}
+	for _, r := range m.ratings.GetRatings() {
+		res = append(res, *r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Rating != res[j].Rating {
+			return res[i].Rating > res[j].Rating
+		}
+		return res[i].Player < res[j].Player
+	})
+	return res, nil
+}
+
+//Queue puts a player in the ranked queue, and pairs them right away with the waiting player of the closest rating, if close enough.
+//Paired players get a ranked match already started, so they only have to play the game of their entry
+func (m minesweeper) Queue(ctx context.Context, req models.QueueRequest) (res *models.Ticket, err error) {
+
+	if req.Player == "" {
+		return &models.Ticket{}, errors.New("Player doesnt have a name.")
+	}
+	r, err := m.Rating(ctx, req.Player)
+	if err != nil {
+		return &models.Ticket{}, err
+	}
+	ticket := models.Ticket{
+		Player: req.Player,
+		Rating: r.Rating,
+		Joined: time.Now().UTC(),
+		Status: models.TicketWaiting,
+	}
+	err = m.ratings.UpdateQueue(func(queue []models.Ticket) ([]models.Ticket, error) {
+		if i := findTicket(queue, req.Player); i >= 0 {
+			if queue[i].Status == models.TicketWaiting {
+				return nil, errors.New("Player already queued")
+			}
+			//the ticket of the previous match isn't needed anymore
+			queue = append(queue[:i], queue[i+1:]...)
+		}
+		return append(queue, ticket), nil
+	})
+	if err != nil {
+		return &models.Ticket{}, err
+	}
+	return m.pair(ctx, req.Player)
+}
+
+//Ticket returns the ticket of a player in the ranked queue. Waiting players are looked an opponent for again,
+//since players further apart can be paired the longer they wait
+func (m minesweeper) Ticket(ctx context.Context, player string) (res *models.Ticket, err error) {
+
+	if m.ratings == nil {
+		return &models.Ticket{}, errors.New("Ratings aren't available")
+	}
+	return m.pair(ctx, player)
+}
+
+//pair looks for an opponent for a player waiting in the queue, and starts a ranked match for both of them when one is found
+func (m minesweeper) pair(ctx context.Context, player string) (*models.Ticket, error) {
+
+	u, err := uuid.NewV4()
+	if err != nil {
+		return &models.Ticket{}, err
+	}
+	name := "ranked-" + u.String()
+
+	var ticket models.Ticket
+	var opponent string
+	err = m.ratings.UpdateQueue(func(queue []models.Ticket) ([]models.Ticket, error) {
+		i := findTicket(queue, player)
+		if i < 0 {
+			return nil, errors.New("Player isn't queued")
+		}
+		ticket = queue[i]
+		if ticket.Status != models.TicketWaiting {
+			return queue, nil
+		}
+
+		now := time.Now()
+		best := -1
+		for j, other := range queue {
+			if j == i || other.Status != models.TicketWaiting {
+				continue
+			}
+			gap := math.Abs(other.Rating - ticket.Rating)
+			if gap > math.Max(window(ticket, now), window(other, now)) {
+				continue
+			}
+			if best < 0 || gap < math.Abs(queue[best].Rating-ticket.Rating) {
+				best = j
+			}
+		}
+		if best < 0 {
+			return queue, nil
+		}
+		for _, k := range []int{i, best} {
+			queue[k].Status = models.TicketMatched
+			queue[k].Match = name
+		}
+		opponent = queue[best].Player
+		ticket = queue[i]
+		return queue, nil
+	})
+	if err != nil {
+		return &models.Ticket{}, err
+	}
+	if opponent == "" {
+		return &ticket, nil
+	}
+
+	if err := m.startRanked(ctx, name, []string{ticket.Player, opponent}); err != nil {
+		//both players go back to waiting, as if they were never paired
+		m.ratings.UpdateQueue(func(queue []models.Ticket) ([]models.Ticket, error) {
+			for i := range queue {
+				if queue[i].Match == name {
+					queue[i].Status = models.TicketWaiting
+					queue[i].Match = ""
+				}
+			}
+			return queue, nil
+		})
+		return &models.Ticket{}, err
+	}
+	return &ticket, nil
+}
+
+//startRanked creates a ranked match for the given players and starts it
+func (m minesweeper) startRanked(ctx context.Context, name string, players []string) error {
+
+	if _, err := m.CreateMatch(ctx, &models.Match{Name: name, Players: len(players), Ranked: true}); err != nil {
+		return err
+	}
+	for _, player := range players {
+		if _, err := m.JoinMatch(ctx, models.JoinRequest{Match: name, Player: player}); err != nil {
+			return err
+		}
+	}
+	_, err := m.StartMatch(ctx, name)
+	return err
+}
+
+//rateMatch updates the ratings of the players of a finished ranked match. Each player beats those below them in the standings,
+//and players the standings can't tell apart draw
+func (m minesweeper) rateMatch(ctx context.Context, name string) error {
+
+	standings, err := m.Standings(ctx, name)
+	if err != nil {
+		return err
+	}
+	players := make([]string, len(standings.Standings))
+	ranks := make([]int, len(standings.Standings))
+	for i, s := range standings.Standings {
+		players[i] = s.Player
+		ranks[i] = i
+		if i > 0 {
+			prev := standings.Standings[i-1]
+			if prev.Player != standings.Match.Winner && prev.Alive == s.Alive && prev.Revealed == s.Revealed {
+				ranks[i] = ranks[i-1]
+			}
+		}
+	}
+	return m.rate(name, players, ranks)
+}
+
+//rate updates the ratings of the players of a match given where each of them ended, the lowest rank being the best.
+//The match is a rating period on its own, and every player is rated against the others as they were before it
+func (m minesweeper) rate(match string, players []string, ranks []int) error {
+
+	if m.ratings == nil {
+		return errors.New("Ratings aren't available")
+	}
+	return m.ratings.UpdateRatings(players, func(ratings []*models.PlayerRating) error {
+		before := make([]rating.Rating, len(ratings))
+		for i, r := range ratings {
+			before[i] = rating.Rating{Rating: r.Rating, Deviation: r.Deviation, Volatility: r.Volatility}
+			if r.Matches == 0 {
+				before[i] = rating.New()
+			}
+		}
+
+		now := time.Now().UTC()
+		for i, r := range ratings {
+			var results []rating.Result
+			for j := range ratings {
+				if i == j {
+					continue
+				}
+				result := rating.Result{Opponent: before[j], Score: 0.5}
+				if ranks[i] < ranks[j] {
+					result.Score = 1
+				} else if ranks[i] > ranks[j] {
+					result.Score = 0
+				}
+				results = append(results, result)
+			}
+			after := before[i].Update(results)
+			r.Rating, r.Deviation, r.Volatility = after.Rating, after.Deviation, after.Volatility
+			r.Matches++
+			r.History = append(r.History, models.RatingChange{
+				Match:     match,
+				Time:      now,
+				Rating:    after.Rating,
+				Deviation: after.Deviation,
+				Change:    after.Rating - before[i].Rating,
+			})
+		}
+		return nil
+	})
+}
+
+//window is how many rating points apart a waiting player can be paired
+func window(ticket models.Ticket, now time.Time) float64 {
+	return matchWindow + windowGrowth*now.Sub(ticket.Joined).Seconds()
+}
+
+//findTicket returns where the ticket of a player is in the queue, or -1 if the player isn't queued
+func findTicket(queue []models.Ticket, player string) int {
+
+	for i, ticket := range queue {
+		if ticket.Player == player {
+			return i
+		}
+	}
+	return -1
+}
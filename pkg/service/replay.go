@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
+)
+
+//Rebuild applies the events of a replay over its initial board, in order, and returns the resulting game.
+//Since the replay is the full history of a game, the result is the same game the db has stored, save for the tokens of shared games
+func Rebuild(replay *models.Replay) (*models.Game, error) {
+
+	game := &models.Game{
+		Name:          replay.Name,
+		Rows:          replay.Rows,
+		Columns:       replay.Columns,
+		Mines:         replay.Mines,
+		Board:         copyBoard(replay.Board),
+		UndoLimit:     replay.UndoLimit,
+		UndoPenalty:   replay.UndoPenalty,
+		Practice:      replay.UndoLimit > 0,
+		Lives:         replay.Lives,
+		Mode:          replay.Mode,
+		Topology:      replay.Topology,
+		Layers:        replay.Layers,
+		MaxMines:      replay.MaxMines,
+		Neighbourhood: replay.Neighbourhood,
+		Offsets:       replay.Offsets,
+		TimeLimit:     replay.TimeLimit,
+		MaxClicks:     replay.MaxClicks,
+		Origin:        replay.Origin,
+		Previous:      replay.Previous,
+		Attempt:       replay.Attempt,
+	}
+	geometry := topology.For(game.Topology, game.Layers, game.Offsets).Geometry()
+	game.Geometry = &geometry
+	//replays from before lives, or from other programs, are classic games
+	if game.Lives == 0 {
+		game.Lives = 1
+	}
+	//the states undos go back to
+	var history []*models.Game
+
+	for i, event := range replay.Events {
+		switch event.Action {
+		case models.ActionCreate:
+			game.Status = models.StatusNew
+			game.Created = event.Time
+			if game.TimeLimit > 0 {
+				deadline := event.Time.Add(time.Duration(game.TimeLimit) * time.Second)
+				game.Deadline = &deadline
+			}
+		case models.ActionTimeout:
+			if err := transition(game, models.StatusTimedOut); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+		case models.ActionClick:
+			history = append(history, copyGame(game))
+			row, err := boardRow(game, event.Layer, event.Row)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+			discovered := game.Discovered
+			if err := click(game, event.Player, row, event.Column); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+			countClick(game, event.Player, discovered, game.Board[row][event.Column].Mine)
+		case models.ActionFlag:
+			row, err := boardRow(game, event.Layer, event.Row)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+			if err := flagCell(game, row, event.Column); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+		case models.ActionChord:
+			history = append(history, copyGame(game))
+			row, err := boardRow(game, event.Layer, event.Row)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+			discovered, lives := game.Discovered, game.Lives
+			if err := chord(game, row, event.Column); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+			countClick(game, event.Player, discovered, game.Lives < lives)
+		case models.ActionPause:
+			if err := pause(game, event.Time); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+		case models.ActionResume:
+			if err := resume(game, event.Time); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+		case models.ActionSurrender:
+			if err := surrender(game, event.Player, event.Time); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+		case models.ActionRestart, models.ActionRetry:
+			game.Next = event.Game
+		case models.ActionHint:
+			game.Hints++
+			game.Assisted = true
+		case models.ActionJoin:
+			join(game, event.Player)
+		case models.ActionUndo:
+			if len(history) == 0 {
+				return nil, fmt.Errorf("event %d: nothing to undo", i)
+			}
+			if err := undo(game, history[len(history)-1]); err != nil {
+				return nil, fmt.Errorf("event %d: %v", i, err)
+			}
+			history = history[:len(history)-1]
+		default:
+			return nil, fmt.Errorf("event %d: unknown action %q", i, event.Action)
+		}
+	}
+	return game, nil
+}
+
+//copyBoard returns a deep copy of a board, so changes in one of them don't affect the other
+func copyBoard(board []models.CellRow) []models.CellRow {
+
+	cp := make([]models.CellRow, len(board))
+	for i, row := range board {
+		cp[i] = make(models.CellRow, len(row))
+		copy(cp[i], row)
+	}
+	return cp
+}
+
+//copyGame returns a copy of a game with its own board
+func copyGame(game *models.Game) *models.Game {
+
+	cp := *game
+	cp.Board = copyBoard(game.Board)
+	return &cp
+}
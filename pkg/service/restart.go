@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+//Surrender gives a game up. The game is over as abandoned, so its board shows where every mine was.
+//In flags games the other player wins, and in matches giving up counts as any other loss
+func (m minesweeper) Surrender(ctx context.Context, name string) (res *models.Game, err error) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.db.GetGame(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.surrender(ctx, game, player); err != nil {
+		return &models.Game{}, err
+	}
+	return view(game), nil
+}
+
+//Restart plays a game again on a new board with the same settings. A game still being played is given up first
+func (m minesweeper) Restart(ctx context.Context, name string) (res *models.Game, err error) {
+	return m.playAgain(ctx, name, models.ActionRestart)
+}
+
+//Retry plays a game again on the same board, mines and all. A game still being played is given up first
+func (m minesweeper) Retry(ctx context.Context, name string) (res *models.Game, err error) {
+	return m.playAgain(ctx, name, models.ActionRetry)
+}
+
+//playAgain creates the game that restarts or retries another, as the action says. The new game is created from the replay
+//of the original, so it gets the settings the original was created with, rather than what is left of its lives or its clock.
+//Both games are linked, and the history of the original records which game played it again. Each game is played again once,
+//later attempts play again the last one. The original is only given up once the new game is there, so a game that can't be
+//played again is left as it was
+func (m minesweeper) playAgain(ctx context.Context, name string, action string) (*models.Game, error) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.db.GetGame(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if game.Match != "" || game.Daily != "" {
+		return &models.Game{}, errors.New("Games of matches and daily challenges can't be played again")
+	}
+	if game.Next != "" {
+		return &models.Game{}, fmt.Errorf("Game was already played again as %s", game.Next)
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if !over(game) {
+		if err := allow(game, models.ActionSurrender); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	replay, err := m.db.GetReplay(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+
+	origin := game.Origin
+	if origin == "" {
+		origin = game.Name
+	}
+	again := &models.Game{
+		Name:          fmt.Sprintf("%s-%d", origin, game.Attempt+1),
+		Rows:          replay.Rows,
+		Columns:       replay.Columns,
+		Mines:         replay.Mines,
+		UndoLimit:     replay.UndoLimit,
+		UndoPenalty:   replay.UndoPenalty,
+		Lives:         replay.Lives,
+		Mode:          replay.Mode,
+		Topology:      replay.Topology,
+		Layers:        replay.Layers,
+		MaxMines:      replay.MaxMines,
+		Neighbourhood: replay.Neighbourhood,
+		Offsets:       replay.Offsets,
+		TimeLimit:     replay.TimeLimit,
+		MaxClicks:     replay.MaxClicks,
+		Player:        game.Player,
+		Origin:        origin,
+		Previous:      game.Name,
+		Attempt:       game.Attempt + 1,
+	}
+	//the replay keeps the board before any click, which is the one a retry plays on
+	if action == models.ActionRetry {
+		again.Board = copyBoard(replay.Board)
+	}
+	if err := m.createGame(again); err != nil {
+		return &models.Game{}, err
+	}
+	if !over(game) {
+		if err := m.surrender(ctx, game, player); err != nil {
+			if err := m.db.DeleteGame(again.Name); err != nil {
+				m.logger.Log("method", "playAgain", "game", again.Name, "error", err)
+			}
+			return &models.Game{}, err
+		}
+	}
+
+	event := models.Event{Action: action, Time: time.Now().UTC(), Player: player, Game: again.Name}
+	game.Next = again.Name
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	m.publish(game, event)
+	return view(again), nil
+}
+
+//surrender gives a game up for a player. The caller holds the lock of the game
+func (m minesweeper) surrender(ctx context.Context, game *models.Game, player string) error {
+
+	if err := allow(game, models.ActionSurrender); err != nil {
+		return err
+	}
+	event := models.Event{Action: models.ActionSurrender, Time: time.Now().UTC(), Player: player}
+	if err := surrender(game, player, event.Time); err != nil {
+		return err
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return err
+	}
+	m.publish(game, event)
+	if game.Match != "" {
+		return m.finishMatch(ctx, game)
+	}
+	if game.Ranked && game.Winner != "" {
+		return m.rate(game.Name, []string{game.Winner, opponent(game, game.Winner)}, []int{0, 1})
+	}
+	return nil
+}
+
+//surrender ends a game given up by a player at the given time. A paused game stays paused until then,
+//and the other player of a flags game, if there is one, wins it
+func surrender(game *models.Game, player string, at time.Time) error {
+
+	if err := transition(game, models.StatusAbandoned); err != nil {
+		return err
+	}
+	if game.PausedAt != nil {
+		game.PauseTime += at.Sub(*game.PausedAt).Seconds()
+		game.PausedAt = nil
+	}
+	if game.Mode == models.ModeFlags {
+		if other := opponent(game, player); other != player {
+			game.Winner = other
+		}
+	}
+	return nil
+}
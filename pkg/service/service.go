@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	crand "crypto/rand"
 	"errors"
+	"fmt"
 	"math/rand"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/minesweeper/pkg/db"
 	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/solver"
+	"github.com/minesweeper/pkg/topology"
 )
 
 const (
@@ -17,6 +23,14 @@ const (
 	defaultMines   = 14
 	maxRows        = 36
 	maxColumns     = 36
+	maxLayers      = 16
+
+	//cube boards are played on a 6x6x6 board unless told otherwise
+	defaultLayers    = 6
+	defaultCubeRows  = 6
+	defaultCubeMines = 30
+
+	defaultUndoPenalty = 10 //Seconds added for each undo when the game doesn't set its own penalty
 )
 
 // Minesweepersvc interface that defines the
@@ -26,7 +40,34 @@ type Minesweepersvc interface {
 	NewGame(ctx context.Context, game *models.Game) (err error)
 	LoadGame(ctx context.Context, name string) (res *models.Game, err error)
 	SaveGame(ctx context.Context, game *models.Game) (err error)
+	ListGames(ctx context.Context, query models.GameQuery) (res *models.GamePage, err error)
+	DeleteGame(ctx context.Context, req models.DeleteGameRequest) (err error)
 	Click(ctx context.Context, req models.ClickRequest) (res *models.Game, err error)
+	Flag(ctx context.Context, req models.ClickRequest) (res *models.Game, err error)
+	Chord(ctx context.Context, req models.ClickRequest) (res *models.Game, err error)
+	Replay(ctx context.Context, name string) (res *models.Replay, err error)
+	ExportLayout(ctx context.Context, name string) (res models.Layout, err error)
+	Hint(ctx context.Context, name string) (res *models.Hint, err error)
+	Undo(ctx context.Context, name string) (res *models.Game, err error)
+	CreateMatch(ctx context.Context, match *models.Match) (res *models.Match, err error)
+	JoinMatch(ctx context.Context, req models.JoinRequest) (res *models.Game, err error)
+	StartMatch(ctx context.Context, name string) (res *models.Match, err error)
+	Standings(ctx context.Context, name string) (res *models.Standings, err error)
+	JoinGame(ctx context.Context, req models.JoinGameRequest) (res *models.Session, err error)
+	Subscribe(ctx context.Context, name string) (res <-chan models.Update, err error)
+	Scores(ctx context.Context, name string) (res *models.Scoreboard, err error)
+	Rating(ctx context.Context, player string) (res *models.PlayerRating, err error)
+	Ladder(ctx context.Context) (res []models.PlayerRating, err error)
+	Queue(ctx context.Context, req models.QueueRequest) (res *models.Ticket, err error)
+	Ticket(ctx context.Context, player string) (res *models.Ticket, err error)
+	Daily(ctx context.Context, player string) (res *models.Game, err error)
+	DailyLeaderboard(ctx context.Context, date string) (res *models.Leaderboard, err error)
+	Layer(ctx context.Context, req models.LayerRequest) (res *models.Slice, err error)
+	Pause(ctx context.Context, name string) (res *models.Game, err error)
+	Resume(ctx context.Context, name string) (res *models.Game, err error)
+	Surrender(ctx context.Context, name string) (res *models.Game, err error)
+	Restart(ctx context.Context, name string) (res *models.Game, err error)
+	Retry(ctx context.Context, name string) (res *models.Game, err error)
 }
 
 // MinesweeperResponse is returned from the
@@ -41,6 +82,11 @@ type minesweeper struct {
 	logger      log.Logger
 	minesweeper MinesweeperResponse
 	db          db.MineDBManager
+	matches     db.MatchDBManager
+	broker      *broker
+	ratings     db.RatingDBManager
+	dailies     db.DailyDBManager
+	secret      []byte //Mixed into the seed of daily challenges, so their boards can't be worked out ahead
 }
 
 // NewBasicService returns an instance of
@@ -52,13 +98,37 @@ func NewBasicService(logger log.Logger) Minesweepersvc {
 	if err != nil {
 		logger.Log("method", "NewBasicService", "error", err)
 	}
+	secret, err := dailySecret()
+	if err != nil {
+		logger.Log("method", "NewBasicService", "error", err)
+	}
+	storage := db.New()
 	return minesweeper{
 		logger:      logger,
 		minesweeper: sweeper,
-		db:          db.New(),
+		db:          storage,
+		matches:     storage,
+		broker:      newBroker(),
+		ratings:     storage,
+		dailies:     storage,
+		secret:      secret,
 	}
 }
 
+//dailySecret returns the secret of daily challenges, from the MINESWEEPER_DAILY_SECRET environment variable.
+//Without it the secret is random, so each instance, and each restart, deals its own daily boards
+func dailySecret() ([]byte, error) {
+
+	if secret := os.Getenv("MINESWEEPER_DAILY_SECRET"); secret != "" {
+		return []byte(secret), nil
+	}
+	secret := make([]byte, 32)
+	if _, err := crand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
 // New returns a fully initialized instance of
 // the Minesweepersvc with middlewares
 func New(logger log.Logger) Minesweepersvc {
@@ -89,36 +159,173 @@ func retrieveSweeper() (mr MinesweeperResponse, err error) {
 }
 
 //NewGame sets default values in the new game. Also creates the board and populates it with numbers and mines.
-//Once the game is created, its status is "new", and is inserted in the db for future use
+//Once the game is created, its status is new, and is inserted in the db for future use
 func (m minesweeper) NewGame(ctx context.Context, game *models.Game) (err error) {
 
+	//games only take part in matches, or in the daily challenge, when those create them. The player is kept
+	//as the owner of the game, so it can be found among the games of the player
+	game.Match = ""
+	game.Start = nil
+	game.Daily = ""
+	//boards are placed by the service, and games are only played again through restart and retry
+	game.Board = nil
+	game.Origin = ""
+	game.Previous = ""
+	game.Next = ""
+	game.Attempt = 0
+	if err := m.createGame(game); err != nil {
+		return err
+	}
+	//the game is handed back to whoever created it
+	*game = *view(game)
+	return nil
+}
+
+//createGame creates a new game, as NewGame does, keeping the match it is part of. A game that already has a board,
+//as retries do, is played on it
+func (m minesweeper) createGame(game *models.Game) error {
+
 	if game.Name == "" {
 		return errors.New(models.ErrNoNameGame)
 	}
+	//a game taking the name of a daily challenge would be the one its player gets that day
+	if strings.HasPrefix(game.Name, dailyPrefix) && game.Daily == "" {
+		return errors.New("Names starting with daily- are kept for daily challenges")
+	}
+	//A game with a layout takes its size and mines from it
+	if game.Layout != "" {
+		if err := layoutBoard(game); err != nil {
+			return err
+		}
+	}
+	if game.Mode != "" && game.Mode != models.ModeCoop && game.Mode != models.ModeFlags {
+		return errors.New("unknown mode")
+	}
+	if _, err := topology.Get(game.Topology); err != nil {
+		return err
+	}
+	if err := layers(game); err != nil {
+		return err
+	}
+	if err := neighbourhood(game); err != nil {
+		return err
+	}
+	geometry := topology.For(game.Topology, game.Layers, game.Offsets).Geometry()
+	game.Geometry = &geometry
+	if game.Mode == models.ModeFlags && game.Layout == "" && game.Rows == 0 && game.Columns == 0 && game.Mines == 0 {
+		game.Rows, game.Columns, game.Mines = defaultFlagsRows, defaultFlagsColumns, defaultFlagsMines
+	}
 	if game.Rows == 0 {
 		game.Rows = defaultRows
 	}
 	if game.Columns == 0 {
 		game.Columns = defaultColumns
 	}
-	if game.Mines == 0 {
+	if game.Mines == 0 && game.Layout == "" {
 		game.Mines = defaultMines
 	}
+	if game.Mode == models.ModeFlags && game.Mines%2 == 0 {
+		return errors.New("flags games need an odd number of mines, so there are no draws")
+	}
+	if err := checkMaxMines(game); err != nil {
+		return err
+	}
+	if game.TimeLimit < 0 || game.MaxClicks < 0 {
+		return errors.New("time limit and max clicks can't be negative")
+	}
+	if game.Mode == models.ModeFlags && (game.TimeLimit > 0 || game.MaxClicks > 0) {
+		return errors.New("flags games have no time limit nor max clicks")
+	}
+	if game.Mode == models.ModeFlags && game.UndoLimit > 0 {
+		return errors.New("flags games can't be undone")
+	}
+	if game.Ranked && game.Mode != models.ModeFlags {
+		return errors.New("only flags games can be ranked on their own")
+	}
+	if game.Ranked && game.Layout != "" {
+		return errors.New("ranked games can't have a layout")
+	}
+	//a classic game ends with the first mine
+	if game.Lives == 0 {
+		game.Lives = 1
+	}
+	if game.Lives < 0 {
+		return errors.New("lives can't be negative")
+	}
+	if game.UndoLimit < 0 {
+		return errors.New("undo limit can't be negative")
+	}
+	if game.UndoLimit > 0 && game.UndoPenalty <= 0 {
+		game.UndoPenalty = defaultUndoPenalty
+	}
 	if game.Rows > maxRows {
 		game.Rows = maxRows
 	}
 	if game.Columns > maxColumns {
 		game.Columns = maxColumns
 	}
+	if game.Mines > cells(game)*game.MaxMines {
+		return errors.New("too many mines for the board")
+	}
 
 	if err := newBoard(game); err != nil {
 		return err
 	}
-	game.Status = "new"
+	//the board already holds the layout, keeping it would only duplicate it in every response
+	game.Layout = ""
+	game.Status = models.StatusNew
+	game.Hints = 0
+	game.Assisted = false
+	game.Undos = 0
+	game.Penalty = 0
+	//players join games of several players once created
+	game.Stats = nil
+	game.Tokens = nil
+	game.Turn = ""
+	game.Scores = nil
+	game.Winner = ""
+	//taking clicks back is only for practice
+	game.Practice = game.UndoLimit > 0
+	//the clock of timed games starts right away
+	created := time.Now().UTC()
+	game.Clicks = 0
+	game.Created = created
+	game.Deadline = nil
+	if game.TimeLimit > 0 {
+		deadline := created.Add(time.Duration(game.TimeLimit) * time.Second)
+		game.Deadline = &deadline
+	}
 	//Here we should save the game in order to load it in the future
 	if err := m.db.InsertGame(game); err != nil {
 		return err
 	}
+	//The replay keeps its own copy of the board, since the game's board changes with every click
+	replay := &models.Replay{
+		Name:          game.Name,
+		Rows:          game.Rows,
+		Columns:       game.Columns,
+		Mines:         game.Mines,
+		UndoLimit:     game.UndoLimit,
+		UndoPenalty:   game.UndoPenalty,
+		Lives:         game.Lives,
+		Mode:          game.Mode,
+		Topology:      game.Topology,
+		Layers:        game.Layers,
+		MaxMines:      game.MaxMines,
+		Neighbourhood: game.Neighbourhood,
+		Offsets:       game.Offsets,
+		TimeLimit:     game.TimeLimit,
+		MaxClicks:     game.MaxClicks,
+		Origin:        game.Origin,
+		Previous:      game.Previous,
+		Attempt:       game.Attempt,
+		Board:         copyBoard(game.Board),
+		Events:        []models.Event{{Action: models.ActionCreate, Time: created}},
+	}
+	if err := m.db.InsertReplay(replay); err != nil {
+		return err
+	}
+	m.schedule(game)
 	return nil
 
 }
@@ -126,6 +333,16 @@ func (m minesweeper) NewGame(ctx context.Context, game *models.Game) (err error)
 //LoadGame grabs and return a game by its name from the db
 func (m minesweeper) LoadGame(ctx context.Context, name string) (res *models.Game, err error) {
 
+	game, err := m.load(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	return view(game), nil
+}
+
+//load gets a game from the db for the service to work on. Paused games come without their board
+func (m minesweeper) load(name string) (*models.Game, error) {
+
 	if name == "" {
 		return nil, errors.New(models.ErrNoNameGame)
 	}
@@ -135,10 +352,31 @@ func (m minesweeper) LoadGame(ctx context.Context, name string) (res *models.Gam
 	if err != nil {
 		return &models.Game{}, err
 	}
+	//nobody gets to study the board of a paused game
+	hide(game)
 	return game, nil
 
 }
 
+//view is a game as its players get to see it. Until the game is over, the cells that haven't been clicked keep their mines,
+//and the numbers that would give them away, to themselves. Every game the service hands out goes through it
+func view(game *models.Game) *models.Game {
+
+	cp := copyGame(game)
+	hide(cp)
+	if over(cp) {
+		return cp
+	}
+	for i := range cp.Board {
+		for j := range cp.Board[i] {
+			if cell := &cp.Board[i][j]; !cell.Clicked {
+				cell.Mine, cell.Mines, cell.Number = false, 0, 0
+			}
+		}
+	}
+	return cp
+}
+
 //SaveGame update the current status of the game into the db. Used when the user makes moves (Click)
 func (m minesweeper) SaveGame(ctx context.Context, game *models.Game) (err error) {
 
@@ -152,28 +390,350 @@ func (m minesweeper) SaveGame(ctx context.Context, game *models.Game) (err error
 //Click updates the game according to the cell the user has clicked.
 func (m minesweeper) Click(ctx context.Context, req models.ClickRequest) (res *models.Game, err error) {
 
+	//clicks on the same game are made one after the other, shared games get many at once
+	unlock := m.db.LockGame(req.Name)
+	defer unlock()
+
 	//First load the game from the db
-	game, err := m.LoadGame(ctx, req.Name)
+	game, err := m.load(req.Name)
 
 	if err != nil {
 		return &models.Game{}, err
 	}
-	//click the specific cell
-	if err := clickCell(game, req.Row, req.Column); err != nil {
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := allow(game, models.ActionClick); err != nil {
+		return &models.Game{}, err
+	}
+	if game.Match != "" {
+		if err := started(game); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	//the game as it was before the click is what an undo goes back to, while there are undos left
+	var before *models.Game
+	if game.Undos < game.UndoLimit {
+		before = copyGame(game)
+	}
+	if game.Mode == models.ModeFlags {
+		if err := yourTurn(game, player); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	//click the specific cell, found in its layer in cube boards
+	row, err := boardRow(game, req.Layer, req.Row)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	discovered := game.Discovered
+	if err := click(game, player, row, req.Column); err != nil {
 
 		return &models.Game{}, err
 	}
+	countClick(game, player, discovered, game.Board[row][req.Column].Mine)
+	if before != nil {
+		if err := m.db.PushHistory(before, game.UndoLimit-game.Undos); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	//record the click so the game can be replayed
+	event := models.Event{
+		Action: models.ActionClick,
+		Time:   time.Now().UTC(),
+		Layer:  req.Layer,
+		Row:    req.Row,
+		Column: req.Column,
+		Player: player,
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Game{}, err
+	}
 	//update the game to its new state
 	if err := m.SaveGame(ctx, game); err != nil {
 		return &models.Game{}, err
 	}
+	m.publish(game, event)
+	if game.Match != "" && over(game) {
+		if err := m.finishMatch(ctx, game); err != nil {
+			return &models.Game{}, err
+		}
+	}
+	if game.Ranked && game.Winner != "" {
+		if err := m.rate(game.Name, []string{game.Winner, opponent(game, game.Winner)}, []int{0, 1}); err != nil {
+			return &models.Game{}, err
+		}
+	}
 
-	return game, nil
+	return view(game), nil
+}
+
+//Layer returns a single layer of a cube board, so it can be drawn as a flat board. Other boards only have layer 0, the whole board
+func (m minesweeper) Layer(ctx context.Context, req models.LayerRequest) (res *models.Slice, err error) {
+
+	game, err := m.LoadGame(ctx, req.Name)
+	if err != nil {
+		return &models.Slice{}, err
+	}
+	if game.Status == models.StatusPaused {
+		return &models.Slice{}, models.StatusError{Operation: "look at", Status: game.Status}
+	}
+	start, err := boardRow(game, req.Layer, 0)
+	if err != nil {
+		return &models.Slice{}, err
+	}
+	return &models.Slice{
+		Name:    game.Name,
+		Layer:   req.Layer,
+		Layers:  len(game.Board) / game.Rows,
+		Rows:    game.Rows,
+		Columns: game.Columns,
+		Status:  game.Status,
+		Board:   game.Board[start : start+game.Rows],
+	}, nil
+}
+
+//Replay returns the initial board of a game along with every event taken on it
+func (m minesweeper) Replay(ctx context.Context, name string) (res *models.Replay, err error) {
+
+	if name == "" {
+		return nil, errors.New(models.ErrNoNameGame)
+	}
+
+	replay, err := m.db.GetReplay(name)
+	if err != nil {
+		return &models.Replay{}, err
+	}
+	game, err := m.db.GetGame(name)
+	if err != nil {
+		return &models.Replay{}, err
+	}
+	//the board of a paused game can be rebuilt from its replay, so the replay waits for the game to be resumed
+	if game.Status == models.StatusPaused {
+		return &models.Replay{}, models.StatusError{Operation: "replay", Status: game.Status}
+	}
+	//the initial board has every mine in it, so games still being played get their events alone
+	if !over(game) {
+		replay.Board = nil
+	}
+	replay.Status = game.Status
+	return replay, nil
+}
+
+//ExportLayout returns the layout of the mines of a game, which can be used to create the same board again
+func (m minesweeper) ExportLayout(ctx context.Context, name string) (res models.Layout, err error) {
+
+	game, err := m.load(name)
+	if err != nil {
+		return "", err
+	}
+	//the layout is where every mine is, so it waits for the game to be over
+	if !over(game) {
+		return "", models.StatusError{Operation: "export", Status: game.Status}
+	}
+	if maxMines(game) > 1 {
+		return "", errors.New("layouts have one mine per cell")
+	}
+	return models.FormatLayout(game.Board), nil
+}
+
+//Hint suggests the next move from what the player can see: a cell that is certainly safe, or else one that is certainly a mine.
+//When nothing is certain, it suggests the cell least likely to be a mine along with the chances of every hidden cell.
+//Hints are counted in the game, which stops being eligible for leaderboards
+func (m minesweeper) Hint(ctx context.Context, name string) (res *models.Hint, err error) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.load(name)
+	if err != nil {
+		return &models.Hint{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Hint{}, err
+	}
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Hint{}, err
+	}
+	if err := allow(game, models.ActionHint); err != nil {
+		return &models.Hint{}, err
+	}
+	if game.Match != "" || game.Mode == models.ModeFlags {
+		return &models.Hint{}, errors.New("Hints aren't allowed against other players")
+	}
+	if maxMines(game) > 1 {
+		return &models.Hint{}, errors.New("Hints need one mine per cell")
+	}
+
+	view := solver.FromGame(game)
+	res = &models.Hint{}
+	deductions := solver.Solve(view)
+	for _, d := range deductions {
+		if !d.Mine {
+			res.Kind, res.Row, res.Column, res.Reason = models.HintSafe, d.Row, d.Column, d.Reason
+			break
+		}
+	}
+	if res.Kind == "" && len(deductions) > 0 {
+		//no safe cell, so every deduction is a mine
+		d := deductions[0]
+		res.Kind, res.Row, res.Column, res.Reason = models.HintMine, d.Row, d.Column, d.Reason
+	}
+	if res.Kind == "" {
+		probabilities, exact := solver.Probabilities(view)
+		if len(probabilities) == 0 {
+			return &models.Hint{}, errors.New("The board doesn't agree with its numbers")
+		}
+		best := probabilities[0]
+		for _, p := range probabilities {
+			if p.Mine < best.Mine {
+				best = p
+			}
+			probability := models.Probability{Column: p.Column, Mine: p.Mine}
+			probability.Layer, probability.Row = layerRow(game, p.Row)
+			res.Probabilities = append(res.Probabilities, probability)
+		}
+		res.Kind, res.Row, res.Column = models.HintGuess, best.Row, best.Column
+		res.Reason = fmt.Sprintf("no cell is certain, this one has the lowest chance of being a mine (%.0f%%)", best.Mine*100)
+		res.Exact = exact
+	}
+	//the solver sees cube boards as the rows of every layer
+	res.Layer, res.Row = layerRow(game, res.Row)
+
+	game.Hints++
+	game.Assisted = true
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Hint{}, err
+	}
+	event := models.Event{Action: models.ActionHint, Time: time.Now().UTC(), Player: player}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Hint{}, err
+	}
+	m.publish(game, event)
+	return res, nil
+}
+
+//Undo takes back the last click of a practice game, restoring the board as it was before it, even if the click was on a mine.
+//Each undo adds the penalty of the game to its time, and no more undos are allowed once the limit of the game is reached
+func (m minesweeper) Undo(ctx context.Context, name string) (res *models.Game, err error) {
+
+	unlock := m.db.LockGame(name)
+	defer unlock()
+
+	game, err := m.load(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	player, err := player(ctx, game)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if game.UndoLimit == 0 {
+		return &models.Game{}, errors.New("Undo is only allowed in practice games")
+	}
+	if game.Undos >= game.UndoLimit {
+		return &models.Game{}, errors.New("No undos left")
+	}
+	//time spent can't be taken back
+	if err := m.checkClock(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	if err := allow(game, models.ActionUndo); err != nil {
+		return &models.Game{}, err
+	}
+	previous, err := m.db.PopHistory(name)
+	if err != nil {
+		return &models.Game{}, err
+	}
+	if err := undo(game, previous); err != nil {
+		return &models.Game{}, err
+	}
+
+	event := models.Event{
+		Action: models.ActionUndo,
+		Time:   time.Now().UTC(),
+		Player: player,
+	}
+	if err := m.db.AppendEvent(game.Name, event); err != nil {
+		return &models.Game{}, err
+	}
+	if err := m.SaveGame(ctx, game); err != nil {
+		return &models.Game{}, err
+	}
+	m.publish(game, event)
+	return view(game), nil
+}
+
+//undo restores the board of a game to a previous state. Hints and undos already taken are kept
+func undo(game *models.Game, previous *models.Game) error {
+
+	if !game.Status.CanUndoTo(previous.Status) {
+		return models.StatusError{To: previous.Status, Status: game.Status}
+	}
+	game.Status = previous.Status
+	game.Board = previous.Board
+	game.Discovered = previous.Discovered
+	game.Lives = previous.Lives
+	game.Clicks = previous.Clicks
+	game.Undos++
+	game.Penalty += game.UndoPenalty
+	return nil
+}
+
+//click clicks a cell as the mode of the game says, and counts the click. A game is in progress after its first click, unless it ended with it
+func click(game *models.Game, player string, row int, column int) error {
+
+	var err error
+	if game.Mode == models.ModeFlags {
+		err = flagsClick(game, player, row, column)
+	} else {
+		err = clickCell(game, row, column)
+	}
+	if err != nil {
+		return err
+	}
+	if game.Status == models.StatusNew {
+		if err := transition(game, models.StatusInProgress); err != nil {
+			return err
+		}
+	}
+	return spend(game)
 }
 
 func clickCell(game *models.Game, row int, column int) error {
-	//Check that row and column arent out of bounds
-	if row > game.Rows-1 || row < 0 {
+	if err := checkCell(game, row, column); err != nil {
+		return err
+	}
+	//A mine costs a life. It's revealed and flagged, so the player can go on around it, until the last life is lost
+	if game.Board[row][column].Mine == true {
+		game.Board[row][column].Clicked = true
+		game.Board[row][column].Flag = true
+		game.Lives--
+		if game.Lives <= 0 {
+			return transition(game, models.StatusLost)
+		}
+		return nil
+	}
+	revealCell(game, row, column)
+
+	//Check for game win. Discovered only counts safe cells, mines hit are left out. Cells can hold several mines,
+	//so the safe cells are counted rather than taken from the size of the board
+	if game.Discovered == safeCells(game) {
+		return transition(game, models.StatusWon)
+	}
+	return nil
+
+}
+
+//checkCell tells if a cell can be clicked
+func checkCell(game *models.Game, row int, column int) error {
+	//Check that row and column arent out of bounds. Rows are those of every layer in cube boards
+	if row > height(game)-1 || row < 0 {
 		return errors.New("invalid row")
 	}
 	if column > game.Columns-1 || column < 0 {
@@ -183,80 +743,203 @@ func clickCell(game *models.Game, row int, column int) error {
 	if game.Board[row][column].Clicked == true {
 		return errors.New("Already clicked")
 	}
-	//Check for game loss
-	if game.Board[row][column].Mine == true {
-		game.Status = "game_over"
-		return nil
+	return nil
+}
+
+//revealCell clicks a safe cell, and the cells around it when it has no mines in its proximity
+func revealCell(game *models.Game, row int, column int) {
+
+	//If a Cell has 0 mines in its proximity, we click all around it, and so on with the empty cells found.
+	//Cells waiting to be clicked are kept in a stack instead of recursing, since cube boards can have empty areas
+	//too big for the depth of a recursion. Cells already clicked are skipped, so each cell is clicked once
+	pending := []topology.Cell{{Row: row, Column: column}}
+	for len(pending) > 0 {
+		cell := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if checkCell(game, cell.Row, cell.Column) != nil {
+			continue
+		}
+		game.Board[cell.Row][cell.Column].Clicked = true
+		//Discovered tracks how many cells have been clicked (used for win condition)
+		game.Discovered++
+		if game.Board[cell.Row][cell.Column].Number == 0 {
+			pending = append(pending, neighbours(game, cell.Row, cell.Column)...)
+		}
 	}
-	game.Board[row][column].Clicked = true
-	//Discovered tracks how many cells have been clicked (used for win condition)
-	game.Discovered++
-	//If the Cell has 0 mines in its proximity, we click all around it, recursively
-	//This means we do the clickCell() function 9 times inside itself (all surrounding cells)
-	//Complexity of this would be O(9^n), but the conditions of out of bounds and already clicked
-	//mitigate the impact this would have in performance.
-
-	if game.Board[row][column].Number == 0 {
-		for x := row - 1; x < row+2; x++ {
-			for y := column - 1; y < column+2; y++ {
-				clickCell(game, x, y)
-			}
+}
+
+//neighbours returns the cells surrounding a cell, as the topology of the game says
+func neighbours(game *models.Game, row int, column int) []topology.Cell {
+	return topology.For(game.Topology, game.Layers, game.Offsets).Neighbours(height(game), game.Columns, topology.Cell{Row: row, Column: column})
+}
+
+//layers checks the layers of a game. Only cube boards have layers, at least two of them, and they can't come from a layout
+func layers(game *models.Game) error {
+
+	if game.Topology != topology.Cube {
+		if game.Layers > 1 {
+			return errors.New("only cube boards have layers")
 		}
+		game.Layers = 0
+		return nil
+	}
+	if game.Layout != "" {
+		return errors.New("cube boards can't have a layout")
+	}
+	if game.Rows == 0 && game.Columns == 0 && game.Mines == 0 {
+		game.Rows, game.Columns, game.Mines = defaultCubeRows, defaultCubeRows, defaultCubeMines
+	}
+	if game.Layers == 0 {
+		game.Layers = defaultLayers
 	}
+	if game.Layers < 2 || game.Layers > maxLayers {
+		return fmt.Errorf("cube boards have between 2 and %d layers", maxLayers)
+	}
+	return nil
+}
+
+//neighbourhood resolves which cells are the neighbours of a cell in a game, on square boards. Games name one of the known
+//neighbourhoods, whose offsets are filled in, or give their own offsets as the custom one. Others keep the eight cells around
+func neighbourhood(game *models.Game) error {
 
-	//Check for game win
-	if game.Discovered+game.Mines == game.Rows*game.Columns {
-		game.Status = "victory"
+	if game.Neighbourhood == "" && len(game.Offsets) == 0 {
 		return nil
 	}
+	if topology.Of(game.Topology).Geometry().Cell != "square" {
+		return errors.New("only square boards can change their neighbourhood")
+	}
+	if game.Neighbourhood == "" || game.Neighbourhood == topology.Custom {
+		game.Neighbourhood = topology.Custom
+		return topology.CheckOffsets(game.Offsets)
+	}
+	offsets, err := topology.Neighbourhood(game.Neighbourhood)
+	if err != nil {
+		return err
+	}
+	game.Offsets = offsets
 	return nil
+}
+
+//height is how many rows the board of a game holds: those of every layer, in cube boards
+func height(game *models.Game) int {
+
+	if game.Layers > 1 {
+		return game.Rows * game.Layers
+	}
+	return game.Rows
+}
+
+//cells is how many cells the board of a game has
+func cells(game *models.Game) int {
+	return height(game) * game.Columns
+}
+
+//boardRow returns the row of the board a cell of a layer is in, checking the layer and row are in the board
+func boardRow(game *models.Game, layer int, row int) (int, error) {
+
+	layers := game.Layers
+	if layers == 0 {
+		layers = 1
+	}
+	if layer < 0 || layer >= layers {
+		return 0, errors.New("invalid layer")
+	}
+	if row > game.Rows-1 || row < 0 {
+		return 0, errors.New("invalid row")
+	}
+	return layer*game.Rows + row, nil
+}
+
+//layerRow returns the layer a row of the board is in, and the row within the layer
+func layerRow(game *models.Game, row int) (int, int) {
 
+	if game.Layers > 1 {
+		return row / game.Rows, row % game.Rows
+	}
+	return 0, row
 }
 
-//newBoard populates the board with mines and numbers
+//layoutBoard places the mines of the game as its layout says. Rows, columns and mines are taken from the layout too
+func layoutBoard(game *models.Game) error {
+
+	board, err := models.ParseLayout(game.Layout)
+	if err != nil {
+		return err
+	}
+	if len(board) > maxRows || len(board[0]) > maxColumns {
+		return errors.New("layout is bigger than the maximum board")
+	}
+	game.Rows = len(board)
+	game.Columns = len(board[0])
+	game.Mines = 0
+	for _, row := range board {
+		for _, cell := range row {
+			if cell.Mine {
+				game.Mines++
+			}
+		}
+	}
+	game.Board = board
+	return nil
+}
+
+//newBoard populates the board with mines and numbers. Games with a layout, or retrying another, already have their mines, so only numbers are set
 func newBoard(game *models.Game) error {
 
-	//First we populate the board with mines
-	numCells := game.Rows * game.Columns
+	if game.Board == nil {
+		placeMines(game)
+	}
+	//O(n^2)
+	for i, row := range game.Board {
+		for j := range row {
+			setNumbers(game, i, j)
+		}
+
+	}
+	return nil
+}
+
+//placeMines creates the board and puts its mines in random spots. A spot can take mines until it holds as many as a cell can
+func placeMines(game *models.Game) {
+
+	most := maxMines(game)
+	numCells := cells(game)
 	cells := make(models.CellRow, numCells)
+	//games with the same seed get the same mines
+	seed := game.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(seed))
 	i := 0
 	for i < game.Mines {
 		//Get random spot for mines
-		rand.Seed(time.Now().UnixNano())
-		spot := rand.Intn(numCells)
-		if cells[spot].Mine == false {
+		spot := rnd.Intn(numCells)
+		if cellMines(cells[spot]) < most {
 			cells[spot].Mine = true
+			//cells of classic games only say whether they hold a mine
+			if most > 1 {
+				cells[spot].Mines++
+			}
 			i++
 		}
 	}
 
-	game.Board = make([]models.CellRow, game.Rows)
+	game.Board = make([]models.CellRow, height(game))
 	//Fit in the final board each of the cell rows
 	for c := range game.Board {
 		game.Board[c] = cells[c*game.Columns : ((c + 1) * game.Columns)]
 	}
-	//O(n^2)
-	for i, row := range game.Board {
-		for j, cell := range row {
-			if cell.Mine == true {
-				setNumbers(game, i, j)
-			}
-		}
-
-	}
-	return nil
 }
 
-//setNumbers pluses by 1 the Number value in each cell surrounding a mine
-//when every mine has his neighbours's numbers setted, board is ready
+//setNumbers sets the Number value of a cell to the mines of its neighbours, as many as each of them holds.
+//Numbers are counted from the cell, since custom neighbourhoods don't need to go both ways
+//when every cell has his number setted, board is ready
 func setNumbers(game *models.Game, i int, j int) {
 
-	for x := i - 1; x < i+2; x++ {
-		for y := j - 1; y < j+2; y++ {
-			if !((x < 0) || (x > game.Rows-1) || (y < 0) || (y > game.Columns-1)) && !((x == i) && (y == j)) {
-				game.Board[x][y].Number++
-			}
-		}
+	game.Board[i][j].Number = 0
+	for _, n := range neighbours(game, i, j) {
+		game.Board[i][j].Number += cellMines(game.Board[n.Row][n.Column])
 	}
 
 }
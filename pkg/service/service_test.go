@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/minesweeper/pkg/db"
 
 	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
 	uuid "github.com/nu7hatch/gouuid"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -83,12 +87,9 @@ func TestNewGame(t *testing.T) {
 func TestClick(t *testing.T) {
 
 	game := models.Game{
-		Name:       "already",
-		Columns:    5,
-		Rows:       5,
-		Mines:      5,
-		Discovered: 0,
-		Status:     "start",
+		Name:   "already",
+		Status: "start",
+		Layout: "*...*\n.....\n.....\n*...*\n..*..",
 	}
 
 	u, _ := uuid.NewV4()
@@ -158,3 +159,1254 @@ func TestClick(t *testing.T) {
 	})
 
 }
+
+func TestChord(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	game := models.Game{Name: "chord", Layout: "*...\n....\n....\n...*"}
+	service.NewGame(context.TODO(), &game)
+	service.Click(context.TODO(), models.ClickRequest{Name: "chord", Row: 1, Column: 1})
+
+	Convey("Test Chord", t, func() {
+		Convey("Numbers are only chorded with as many flags around as they say", func() {
+			_, err := service.Chord(context.TODO(), models.ClickRequest{Name: "chord", Row: 1, Column: 1})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Chords click every hidden neighbour without a flag", func() {
+			service.Flag(context.TODO(), models.ClickRequest{Name: "chord", Row: 0, Column: 0})
+			res, err := service.Chord(context.TODO(), models.ClickRequest{Name: "chord", Row: 1, Column: 1})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusWon)
+
+			replay, _ := service.Replay(context.TODO(), "chord")
+			So(replay.Events[len(replay.Events)-1].Action, ShouldEqual, models.ActionChord)
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Status, ShouldEqual, models.StatusWon)
+			So(rebuilt.Discovered, ShouldEqual, res.Discovered)
+		})
+		Convey("Chords that fail leave nothing to undo", func() {
+			practice := models.Game{Name: "chord-undo", Layout: "*...\n....\n....\n...*", UndoLimit: 2, UndoPenalty: 10}
+			service.NewGame(context.TODO(), &practice)
+			service.Click(context.TODO(), models.ClickRequest{Name: "chord-undo", Row: 1, Column: 1})
+			_, err := service.Chord(context.TODO(), models.ClickRequest{Name: "chord-undo", Row: 1, Column: 1})
+			So(err, ShouldNotBeNil)
+
+			res, err := service.Undo(context.TODO(), "chord-undo")
+			So(err, ShouldBeNil)
+			So(res.Discovered, ShouldEqual, 0)
+			So(res.Penalty, ShouldEqual, 10)
+
+			replay, _ := db.GetReplay("chord-undo")
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Discovered, ShouldEqual, res.Discovered)
+			So(rebuilt.Undos, ShouldEqual, res.Undos)
+		})
+	})
+}
+
+func TestReplay(t *testing.T) {
+
+	game := models.Game{
+		Name:    "replayed",
+		Columns: 6,
+		Rows:    6,
+		Mines:   4,
+	}
+
+	clicks := []models.ClickRequest{
+		{Name: "replayed", Row: 0, Column: 0},
+		{Name: "replayed", Row: 5, Column: 5},
+		{Name: "replayed", Row: 2, Column: 3},
+	}
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	service.NewGame(context.TODO(), &game)
+	initial := copyBoard(kept(db, "replayed").Board)
+	//the initial board has every mine in it, so it waits for the game to be over
+	playing, _ := service.Replay(context.TODO(), "replayed")
+	for _, click := range clicks {
+		service.Click(context.TODO(), click)
+	}
+	service.Surrender(context.TODO(), "replayed")
+
+	Convey("Test Replay", t, func() {
+		So(playing.Board, ShouldBeNil)
+		So(playing.Events, ShouldHaveLength, 1)
+		replay, err := service.Replay(context.TODO(), "replayed")
+		So(err, ShouldBeNil)
+
+		Convey("Keeps the initial board", func() {
+			So(replay.Board, ShouldResemble, initial)
+		})
+		Convey("Starts with a create event", func() {
+			So(replay.Events[0].Action, ShouldEqual, models.ActionCreate)
+		})
+		Convey("Rebuilds the current game", func() {
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			stored, _ := service.LoadGame(context.TODO(), "replayed")
+			So(rebuilt, ShouldResemble, stored)
+		})
+		Convey("Inexisting Game", func() {
+			_, err := service.Replay(context.TODO(), "missing")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestLayout(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		layout  models.Layout
+		isValid func(game *models.Game, err error) bool
+	}{
+		{
+			name:   "Numbers from layout",
+			layout: "*..\n.*.\n...\n",
+			isValid: func(game *models.Game, err error) bool {
+				return err == nil && game.Rows == 3 && game.Columns == 3 && game.Mines == 2 &&
+					game.Board[0][1].Number == 2 && game.Board[2][2].Number == 1 && game.Board[2][0].Number == 1
+			},
+		},
+		{
+			name:   "Layout without mines",
+			layout: "...\n...",
+			isValid: func(game *models.Game, err error) bool {
+				return err == nil && game.Mines == 0
+			},
+		},
+		{
+			name:   "Rows of different size",
+			layout: "*..\n..",
+			isValid: func(game *models.Game, err error) bool {
+				return err != nil
+			},
+		},
+		{
+			name:   "Invalid cell",
+			layout: "*.x",
+			isValid: func(game *models.Game, err error) bool {
+				return err != nil
+			},
+		},
+	}
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	Convey("Test Layout", t, func() {
+		for _, c := range cases {
+			Convey(c.name, func() {
+				game := models.Game{Name: c.name, Layout: c.layout}
+				err := service.NewGame(context.TODO(), &game)
+				stored := kept(db, c.name)
+				So(c.isValid(&stored, err), ShouldBeTrue)
+			})
+		}
+		Convey("JSON grid", func() {
+			var game models.Game
+			So(json.Unmarshal([]byte(`{"layout": [[true, false], [false, false]]}`), &game), ShouldBeNil)
+			So(game.Layout, ShouldEqual, models.Layout("*.\n.."))
+			So(json.Unmarshal([]byte(`{"layout": ["*.", ".."]}`), &game), ShouldBeNil)
+			So(game.Layout, ShouldEqual, models.Layout("*.\n.."))
+		})
+		Convey("Export", func() {
+			_, err := service.ExportLayout(context.TODO(), "Numbers from layout")
+			So(err, ShouldHaveSameTypeAs, models.StatusError{})
+			service.Surrender(context.TODO(), "Numbers from layout")
+			layout, err := service.ExportLayout(context.TODO(), "Numbers from layout")
+			So(err, ShouldBeNil)
+			So(layout, ShouldEqual, models.Layout("*..\n.*.\n...\n"))
+		})
+	})
+
+}
+
+func TestHint(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	service.NewGame(context.TODO(), &models.Game{Name: "hinted", Layout: ".*..\n....\n...."})
+	service.Click(context.TODO(), models.ClickRequest{Name: "hinted", Row: 2, Column: 3})
+	service.NewGame(context.TODO(), &models.Game{Name: "untouched", Layout: "*.\n.."})
+
+	Convey("Test Hint", t, func() {
+		Convey("Safe cell", func() {
+			hint, err := service.Hint(context.TODO(), "hinted")
+			So(err, ShouldBeNil)
+			So(hint.Kind, ShouldEqual, models.HintSafe)
+			So(hint.Row, ShouldEqual, 0)
+			So(hint.Column, ShouldEqual, 0)
+			game, _ := service.LoadGame(context.TODO(), "hinted")
+			So(game.Assisted, ShouldBeTrue)
+		})
+		Convey("Guess", func() {
+			hint, err := service.Hint(context.TODO(), "untouched")
+			So(err, ShouldBeNil)
+			So(hint.Kind, ShouldEqual, models.HintGuess)
+			So(hint.Exact, ShouldBeTrue)
+			So(hint.Probabilities, ShouldHaveLength, 4)
+			So(hint.Probabilities[0].Mine, ShouldAlmostEqual, 0.25)
+		})
+		Convey("Finished game", func() {
+			service.Click(context.TODO(), models.ClickRequest{Name: "hinted", Row: 0, Column: 1})
+			_, err := service.Hint(context.TODO(), "hinted")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestUndo(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	service.NewGame(context.TODO(), &models.Game{Name: "practice", Layout: ".*..\n....\n....", UndoLimit: 1, UndoPenalty: 5})
+	safe, _ := service.Click(context.TODO(), models.ClickRequest{Name: "practice", Row: 2, Column: 3})
+	discovered := safe.Discovered
+	service.Click(context.TODO(), models.ClickRequest{Name: "practice", Row: 0, Column: 1})
+	service.NewGame(context.TODO(), &models.Game{Name: "ranked", Layout: ".*..\n....\n...."})
+	service.Click(context.TODO(), models.ClickRequest{Name: "ranked", Row: 2, Column: 3})
+
+	Convey("Test Undo", t, func() {
+		Convey("Takes back a fatal click", func() {
+			game, err := service.Undo(context.TODO(), "practice")
+			So(err, ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusInProgress)
+			So(game.Discovered, ShouldEqual, discovered)
+			So(game.Penalty, ShouldEqual, 5)
+			So(game.Practice, ShouldBeTrue)
+		})
+		Convey("Limit", func() {
+			_, err := service.Undo(context.TODO(), "practice")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Rebuilds undos", func() {
+			replay, _ := db.GetReplay("practice")
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(*rebuilt, ShouldResemble, kept(db, "practice"))
+		})
+		Convey("Not a practice game", func() {
+			_, err := service.Undo(context.TODO(), "ranked")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestLives(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	service.NewGame(context.TODO(), &models.Game{Name: "survivor", Layout: "*.*\n...\n...", Lives: 2})
+	service.NewGame(context.TODO(), &models.Game{Name: "unlucky", Layout: "*.*\n...\n...", Lives: 2})
+	service.NewGame(context.TODO(), &models.Game{Name: "chorder", Layout: "*..\n...\n...", Lives: 2})
+
+	Convey("Test Lives", t, func() {
+		Convey("A mine costs a life", func() {
+			game, err := service.Click(context.TODO(), models.ClickRequest{Name: "survivor", Row: 0, Column: 0})
+			So(err, ShouldBeNil)
+			So(game.Lives, ShouldEqual, 1)
+			So(game.Status, ShouldEqual, models.StatusInProgress)
+			So(game.Board[0][0].Flag, ShouldBeTrue)
+		})
+		Convey("Victory with mines hit", func() {
+			service.Click(context.TODO(), models.ClickRequest{Name: "survivor", Row: 2, Column: 2})
+			game, err := service.Click(context.TODO(), models.ClickRequest{Name: "survivor", Row: 0, Column: 1})
+			So(err, ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusWon)
+		})
+		Convey("Game over with the last life", func() {
+			service.Click(context.TODO(), models.ClickRequest{Name: "unlucky", Row: 0, Column: 0})
+			game, err := service.Click(context.TODO(), models.ClickRequest{Name: "unlucky", Row: 0, Column: 2})
+			So(err, ShouldBeNil)
+			So(game.Lives, ShouldEqual, 0)
+			So(game.Status, ShouldEqual, models.StatusLost)
+		})
+		Convey("Mines hit count as flags for chords", func() {
+			service.Click(context.TODO(), models.ClickRequest{Name: "chorder", Row: 0, Column: 0})
+			service.Click(context.TODO(), models.ClickRequest{Name: "chorder", Row: 1, Column: 1})
+			game, err := service.Chord(context.TODO(), models.ClickRequest{Name: "chorder", Row: 1, Column: 1})
+			So(err, ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusWon)
+		})
+		Convey("Classic game", func() {
+			game := models.Game{Name: "classic"}
+			So(service.NewGame(context.TODO(), &game), ShouldBeNil)
+			So(game.Lives, ShouldEqual, 1)
+		})
+	})
+
+}
+
+func TestMatch(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger:  logger,
+		db:      db,
+		matches: db,
+	}
+
+	service.CreateMatch(context.TODO(), &models.Match{Name: "race", Rows: 6, Columns: 6, Mines: 5})
+	service.JoinMatch(context.TODO(), models.JoinRequest{Match: "race", Player: "alice"})
+	service.JoinMatch(context.TODO(), models.JoinRequest{Match: "race", Player: "bob"})
+
+	Convey("Test Match", t, func() {
+		Convey("Same board for everyone", func() {
+			first, _ := service.ExportLayout(context.TODO(), "race-1")
+			second, _ := service.ExportLayout(context.TODO(), "race-2")
+			So(first, ShouldEqual, second)
+		})
+		Convey("Full lobby", func() {
+			_, err := service.JoinMatch(context.TODO(), models.JoinRequest{Match: "race", Player: "carol"})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Matches that can't be created leave no games behind", func() {
+			_, err := service.CreateMatch(context.TODO(), &models.Match{Name: "race"})
+			So(err, ShouldNotBeNil)
+			service.NewGame(context.TODO(), &models.Game{Name: "clash-2"})
+			_, err = service.CreateMatch(context.TODO(), &models.Match{Name: "clash"})
+			So(err, ShouldNotBeNil)
+			_, err = service.LoadGame(context.TODO(), "clash-1")
+			So(err, ShouldNotBeNil)
+			_, err = service.LoadGame(context.TODO(), "clash-2")
+			So(err, ShouldBeNil)
+		})
+		Convey("No clicks before the start", func() {
+			_, err := service.Click(context.TODO(), models.ClickRequest{Name: "race-1", Row: 0, Column: 0})
+			So(err, ShouldNotBeNil)
+			match, err := service.StartMatch(context.TODO(), "race")
+			So(err, ShouldBeNil)
+			So(match.Status, ShouldEqual, models.MatchStarted)
+			_, err = service.Click(context.TODO(), models.ClickRequest{Name: "race-1", Row: 0, Column: 0})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("First victory wins", func() {
+			//skip the countdown
+			past := time.Now().Add(-time.Second)
+			game, _ := service.LoadGame(context.TODO(), "race-1")
+			game.Start = &past
+			service.SaveGame(context.TODO(), game)
+			for i, row := range game.Board {
+				for j, cell := range row {
+					if !cell.Mine && !game.Board[i][j].Clicked {
+						game, _ = service.Click(context.TODO(), models.ClickRequest{Name: "race-1", Row: i, Column: j})
+					}
+				}
+			}
+			So(game.Status, ShouldEqual, models.StatusWon)
+			standings, err := service.Standings(context.TODO(), "race")
+			So(err, ShouldBeNil)
+			So(standings.Match.Winner, ShouldEqual, "alice")
+			So(standings.Match.Status, ShouldEqual, models.MatchFinished)
+			So(standings.Standings[0].Player, ShouldEqual, "alice")
+			So(standings.Standings[0].Revealed, ShouldEqual, 100)
+		})
+	})
+
+}
+
+func TestFlags(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	service.NewGame(context.TODO(), &models.Game{Name: "duel", Layout: "*.*\n...\n*..", Mode: models.ModeFlags})
+	alice, _ := service.JoinGame(context.TODO(), models.JoinGameRequest{Name: "duel", Player: "alice"})
+	ctxAlice := WithToken(context.TODO(), alice.Token)
+
+	Convey("Test Flags", t, func() {
+		Convey("Even mines are rejected", func() {
+			err := service.NewGame(context.TODO(), &models.Game{Name: "draw", Layout: "*.*\n...\n...", Mode: models.ModeFlags})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Waiting for the opponent", func() {
+			_, err := service.Click(ctxAlice, models.ClickRequest{Name: "duel", Row: 2, Column: 2})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Turns and scores", func() {
+			bob, err := service.JoinGame(context.TODO(), models.JoinGameRequest{Name: "duel", Player: "bob"})
+			So(err, ShouldBeNil)
+			ctxBob := WithToken(context.TODO(), bob.Token)
+
+			_, err = service.Click(ctxBob, models.ClickRequest{Name: "duel", Row: 0, Column: 0})
+			So(err, ShouldNotBeNil)
+			game, err := service.Click(ctxAlice, models.ClickRequest{Name: "duel", Row: 2, Column: 2})
+			So(err, ShouldBeNil)
+			So(game.Turn, ShouldEqual, "bob")
+
+			game, err = service.Click(ctxBob, models.ClickRequest{Name: "duel", Row: 0, Column: 0})
+			So(err, ShouldBeNil)
+			So(game.Turn, ShouldEqual, "bob")
+			game, err = service.Click(ctxBob, models.ClickRequest{Name: "duel", Row: 0, Column: 2})
+			So(err, ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusWon)
+			So(game.Winner, ShouldEqual, "bob")
+
+			scores, err := service.Scores(context.TODO(), "duel")
+			So(err, ShouldBeNil)
+			So(scores.MinesLeft, ShouldEqual, 1)
+			So(scores.Scores[1].Mines, ShouldEqual, 2)
+		})
+	})
+
+}
+
+func TestCoop(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	//every cell of the two middle rows has a mine around it, so each click reveals a single cell
+	service.NewGame(context.TODO(), &models.Game{Name: "team", Layout: "*.*.*.*.\n........\n........\n.*.*.*.*", Mode: models.ModeCoop})
+	alice, _ := service.JoinGame(context.TODO(), models.JoinGameRequest{Name: "team", Player: "alice"})
+	bob, _ := service.JoinGame(context.TODO(), models.JoinGameRequest{Name: "team", Player: "bob"})
+	ctxAlice := WithToken(context.TODO(), alice.Token)
+	ctxBob := WithToken(context.TODO(), bob.Token)
+
+	Convey("Test Coop", t, func() {
+		Convey("Every player gets a token of their own", func() {
+			So(alice.Token, ShouldNotBeEmpty)
+			So(bob.Token, ShouldNotEqual, alice.Token)
+			_, err := service.JoinGame(context.TODO(), models.JoinGameRequest{Name: "team", Player: "alice"})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Moves need the token of a player of the game", func() {
+			_, err := service.Click(context.TODO(), models.ClickRequest{Name: "team", Row: 1, Column: 0})
+			So(err, ShouldNotBeNil)
+			_, err = service.Click(WithToken(context.TODO(), "forged"), models.ClickRequest{Name: "team", Row: 1, Column: 0})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Clicks made at once are made one after the other, and counted for whoever made them", func() {
+			var wg sync.WaitGroup
+			for _, turn := range []struct {
+				ctx context.Context
+				row int
+			}{{ctxAlice, 1}, {ctxBob, 2}} {
+				for column := 0; column < 8; column++ {
+					wg.Add(1)
+					go func(ctx context.Context, row int, column int) {
+						defer wg.Done()
+						service.Click(ctx, models.ClickRequest{Name: "team", Row: row, Column: column})
+					}(turn.ctx, turn.row, column)
+				}
+			}
+			wg.Wait()
+
+			game, err := service.LoadGame(context.TODO(), "team")
+			So(err, ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusInProgress)
+			So(game.Discovered, ShouldEqual, 16)
+			So(game.Stats, ShouldResemble, []models.PlayerStats{
+				{Player: "alice", Clicks: 8, Revealed: 8},
+				{Player: "bob", Clicks: 8, Revealed: 8},
+			})
+
+			replay, _ := db.GetReplay("team")
+			clicks := map[string]int{}
+			for _, event := range replay.Events {
+				if event.Action == models.ActionClick {
+					clicks[event.Player]++
+				}
+			}
+			So(clicks, ShouldResemble, map[string]int{"alice": 8, "bob": 8})
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Stats, ShouldResemble, game.Stats)
+		})
+	})
+
+}
+
+func TestRating(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger:  logger,
+		db:      db,
+		matches: db,
+		ratings: db,
+	}
+
+	Convey("Test Rating", t, func() {
+		Convey("Players are paired from the queue", func() {
+			alice, err := service.Queue(context.TODO(), models.QueueRequest{Player: "alice"})
+			So(err, ShouldBeNil)
+			So(alice.Status, ShouldEqual, models.TicketWaiting)
+
+			bob, err := service.Queue(context.TODO(), models.QueueRequest{Player: "bob"})
+			So(err, ShouldBeNil)
+			So(bob.Status, ShouldEqual, models.TicketMatched)
+			alice, _ = service.Ticket(context.TODO(), "alice")
+			So(alice.Match, ShouldEqual, bob.Match)
+
+			match, err := service.matches.GetMatch(bob.Match)
+			So(err, ShouldBeNil)
+			So(match.Ranked, ShouldBeTrue)
+			So(match.Status, ShouldEqual, models.MatchStarted)
+		})
+		Convey("Ratings after a match", func() {
+			So(service.rate("final", []string{"alice", "bob"}, []int{0, 1}), ShouldBeNil)
+			ladder, err := service.Ladder(context.TODO())
+			So(err, ShouldBeNil)
+			So(len(ladder), ShouldEqual, 2)
+			So(ladder[0].Player, ShouldEqual, "alice")
+			So(ladder[0].Rating, ShouldBeGreaterThan, 1500)
+
+			bob, err := service.Rating(context.TODO(), "bob")
+			So(err, ShouldBeNil)
+			So(bob.Matches, ShouldEqual, 1)
+			So(bob.History[0].Change, ShouldBeLessThan, 0)
+		})
+		Convey("Unrated player", func() {
+			carol, err := service.Rating(context.TODO(), "carol")
+			So(err, ShouldBeNil)
+			So(carol.Rating, ShouldEqual, 1500)
+			So(carol.Matches, ShouldEqual, 0)
+		})
+	})
+
+}
+
+func TestDaily(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger:  logger,
+		db:      db,
+		dailies: db,
+	}
+
+	Convey("Test Daily", t, func() {
+		Convey("Same board for everyone, one attempt each", func() {
+			alice, err := service.Daily(context.TODO(), "alice")
+			So(err, ShouldBeNil)
+			bob, err := service.Daily(context.TODO(), "bob")
+			So(err, ShouldBeNil)
+			So(bob.Name, ShouldNotEqual, alice.Name)
+			So(kept(db, bob.Name).Board, ShouldResemble, kept(db, alice.Name).Board)
+			for _, row := range alice.Board {
+				for _, cell := range row {
+					So(cell.Mine, ShouldBeFalse)
+				}
+			}
+
+			service.Click(context.TODO(), models.ClickRequest{Name: alice.Name, Row: 0, Column: 0})
+			again, err := service.Daily(context.TODO(), "alice")
+			So(err, ShouldBeNil)
+			So(again.Discovered, ShouldNotEqual, 0)
+		})
+		Convey("Leaderboard", func() {
+			game, _ := service.Daily(context.TODO(), "carol")
+			*game = kept(db, game.Name)
+			for i, row := range game.Board {
+				for j, cell := range row {
+					if !cell.Mine {
+						service.Click(context.TODO(), models.ClickRequest{Name: game.Name, Row: i, Column: j})
+					}
+				}
+			}
+			game, _ = service.LoadGame(context.TODO(), game.Name)
+			So(game.Status, ShouldEqual, models.StatusWon)
+
+			board, err := service.DailyLeaderboard(context.TODO(), "")
+			So(err, ShouldBeNil)
+			So(len(board.Entries), ShouldEqual, 1)
+			So(board.Entries[0].Player, ShouldEqual, "carol")
+			So(board.Entries[0].Rank, ShouldEqual, 1)
+		})
+		Convey("Names of daily challenges can't be taken", func() {
+			name := "daily-" + time.Now().UTC().Format(dateLayout) + "-dave"
+			err := service.NewGame(context.TODO(), &models.Game{Name: name, Layout: "........\n.......*", Player: "dave"})
+			So(err, ShouldNotBeNil)
+			game, err := service.Daily(context.TODO(), "dave")
+			So(err, ShouldBeNil)
+			So(game.Name, ShouldEqual, name)
+			So(game.Mines, ShouldBeGreaterThan, 1)
+		})
+		Convey("Invalid date", func() {
+			_, err := service.DailyLeaderboard(context.TODO(), "yesterday")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestTopology(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	game := models.Game{Name: "donut", Layout: "*...\n....\n....\n....", Topology: topology.Torus}
+	service.NewGame(context.TODO(), &game)
+	game = kept(db, "donut")
+
+	Convey("Test Topology", t, func() {
+		Convey("Numbers wrap around the edges", func() {
+			So(game.Board[3][3].Number, ShouldEqual, 1)
+			So(game.Board[0][3].Number, ShouldEqual, 1)
+			So(game.Board[3][0].Number, ShouldEqual, 1)
+			So(game.Board[2][2].Number, ShouldEqual, 0)
+		})
+		Convey("Empty areas wrap around the edges", func() {
+			res, err := service.Click(context.TODO(), models.ClickRequest{Name: "donut", Row: 2, Column: 2})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusWon)
+		})
+		Convey("Hexagons have six neighbours", func() {
+			hex := models.Game{Name: "honeycomb", Layout: "...\n.*.\n...", Topology: topology.Hex}
+			So(service.NewGame(context.TODO(), &hex), ShouldBeNil)
+			hex = kept(db, "honeycomb")
+			So(hex.Geometry.Cell, ShouldEqual, "hexagon")
+			So(hex.Board[0][0].Number, ShouldEqual, 0)
+			So(hex.Board[0][2].Number, ShouldEqual, 1)
+			So(hex.Board[2][0].Number, ShouldEqual, 0)
+
+			res, err := service.Click(context.TODO(), models.ClickRequest{Name: "honeycomb", Row: 0, Column: 0})
+			So(err, ShouldBeNil)
+			So(res.Discovered, ShouldEqual, 3)
+		})
+		Convey("Cubes have layers", func() {
+			cube := models.Game{Name: "rubik", Rows: 4, Columns: 4, Layers: 3, Mines: 1, Topology: topology.Cube}
+			So(service.NewGame(context.TODO(), &cube), ShouldBeNil)
+			cube = kept(db, "rubik")
+			So(len(cube.Board), ShouldEqual, 12)
+			So(cube.Geometry.Layers, ShouldEqual, 3)
+
+			//the one mine numbers every cell around it in three dimensions
+			layer, row, column, numbered := 0, 0, 0, 0
+			for i, r := range cube.Board {
+				for j, cell := range r {
+					if cell.Mine {
+						layer, row, column = i/4, i%4, j
+					}
+					numbered += cell.Number
+				}
+			}
+			So(numbered, ShouldEqual, len(neighbours(&cube, layer*4+row, column)))
+			So(numbered, ShouldBeBetweenOrEqual, 7, 26)
+			_, err := service.Click(context.TODO(), models.ClickRequest{Name: "rubik", Layer: 3, Row: 0, Column: 0})
+			So(err, ShouldNotBeNil)
+			res, err := service.Click(context.TODO(), models.ClickRequest{Name: "rubik", Layer: layer, Row: row, Column: column})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusLost)
+			replay, _ := service.Replay(context.TODO(), "rubik")
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Board, ShouldResemble, res.Board)
+
+			slice, err := service.Layer(context.TODO(), models.LayerRequest{Name: "rubik", Layer: 2})
+			So(err, ShouldBeNil)
+			So(slice.Layers, ShouldEqual, 3)
+			So(slice.Board, ShouldResemble, res.Board[8:12])
+		})
+		Convey("Numbers count the cells of the neighbourhood", func() {
+			knight := models.Game{Name: "knight", Layout: ".....\n.....\n..*..\n.....\n.....", Neighbourhood: topology.Knight}
+			So(service.NewGame(context.TODO(), &knight), ShouldBeNil)
+			knight = kept(db, "knight")
+			So(knight.Offsets, ShouldHaveLength, 8)
+			So(knight.Geometry.Neighbours, ShouldEqual, 8)
+			So(knight.Board[0][1].Number, ShouldEqual, 1)
+			So(knight.Board[3][4].Number, ShouldEqual, 1)
+			So(knight.Board[1][1].Number, ShouldEqual, 0)
+
+			//a cell can neighbour another that doesn't neighbour it back
+			right := models.Game{Name: "right", Layout: ".*.", Offsets: []topology.Cell{{Row: 0, Column: 1}}}
+			So(service.NewGame(context.TODO(), &right), ShouldBeNil)
+			right = kept(db, "right")
+			So(right.Neighbourhood, ShouldEqual, topology.Custom)
+			So(right.Board[0][0].Number, ShouldEqual, 1)
+			So(right.Board[0][2].Number, ShouldEqual, 0)
+
+			So(service.NewGame(context.TODO(), &models.Game{Name: "self", Offsets: []topology.Cell{{Row: 0, Column: 0}}}), ShouldNotBeNil)
+			So(service.NewGame(context.TODO(), &models.Game{Name: "bishop", Neighbourhood: "bishop"}), ShouldNotBeNil)
+			So(service.NewGame(context.TODO(), &models.Game{Name: "hexknight", Topology: topology.Hex, Neighbourhood: topology.Knight}), ShouldNotBeNil)
+		})
+		Convey("Unknown topology", func() {
+			err := service.NewGame(context.TODO(), &models.Game{Name: "sphere", Topology: "sphere"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+}
+
+func TestMultiMines(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	//more mines than cells, so some cells must hold two of them
+	game := models.Game{Name: "crowded", Rows: 4, Columns: 4, Mines: 20, MaxMines: 2, Seed: 7}
+	service.NewGame(context.TODO(), &game)
+	game = kept(db, "crowded")
+
+	//two mines in one cell, next to the number that counts them
+	packed := models.Game{Name: "packed", Rows: 1, Columns: 3, Mines: 2, MaxMines: 2, Status: "new", Board: []models.CellRow{make(models.CellRow, 3)}}
+	packed.Board[0][0].Mine = true
+	packed.Board[0][0].Mines = 2
+	for j := range packed.Board[0] {
+		setNumbers(&packed, 0, j)
+	}
+	db.InsertGame(&packed)
+	db.InsertReplay(&models.Replay{Name: "packed", Rows: 1, Columns: 3, Mines: 2, MaxMines: 2, Board: copyBoard(packed.Board), Events: []models.Event{{Action: models.ActionCreate}}})
+	service.Click(context.TODO(), models.ClickRequest{Name: "packed", Row: 0, Column: 1})
+
+	Convey("Test Multi Mines", t, func() {
+		Convey("Cells hold up to two mines and numbers count all of them", func() {
+			total := 0
+			for i, row := range game.Board {
+				for j, cell := range row {
+					So(cell.Mines, ShouldBeBetweenOrEqual, 0, 2)
+					So(cell.Mine, ShouldEqual, cell.Mines > 0)
+					total += cell.Mines
+					around := 0
+					for _, n := range neighbours(&game, i, j) {
+						around += game.Board[n.Row][n.Column].Mines
+					}
+					So(cell.Number, ShouldEqual, around)
+				}
+			}
+			So(total, ShouldEqual, 20)
+		})
+		Convey("Cells take a flag for each mine they can hold", func() {
+			var res *models.Game
+			for _, flags := range []int{1, 2, 0} {
+				var err error
+				res, err = service.Flag(context.TODO(), models.ClickRequest{Name: "crowded", Row: 0, Column: 0})
+				So(err, ShouldBeNil)
+				So(res.Board[0][0].Flags, ShouldEqual, flags)
+				So(res.Board[0][0].Flag, ShouldEqual, flags > 0)
+			}
+		})
+		Convey("Clearing the safe cells wins", func() {
+			for i, row := range game.Board {
+				for j, cell := range row {
+					if !cell.Mine {
+						service.Click(context.TODO(), models.ClickRequest{Name: "crowded", Row: i, Column: j})
+					}
+				}
+			}
+			res, err := service.LoadGame(context.TODO(), "crowded")
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusWon)
+
+			replay, err := service.Replay(context.TODO(), "crowded")
+			So(err, ShouldBeNil)
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Board, ShouldResemble, res.Board)
+		})
+		Convey("Chords count every flag of a cell", func() {
+			service.Flag(context.TODO(), models.ClickRequest{Name: "packed", Row: 0, Column: 0})
+			_, err := service.Chord(context.TODO(), models.ClickRequest{Name: "packed", Row: 0, Column: 1})
+			So(err, ShouldNotBeNil)
+			service.Flag(context.TODO(), models.ClickRequest{Name: "packed", Row: 0, Column: 0})
+			res, err := service.Chord(context.TODO(), models.ClickRequest{Name: "packed", Row: 0, Column: 1})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusWon)
+		})
+		Convey("Boards only take as many mines as their cells hold", func() {
+			So(service.NewGame(context.TODO(), &models.Game{Name: "full", Rows: 4, Columns: 4, Mines: 33, MaxMines: 2}), ShouldNotBeNil)
+			So(service.NewGame(context.TODO(), &models.Game{Name: "heavy", MaxMines: 4}), ShouldNotBeNil)
+			So(service.NewGame(context.TODO(), &models.Game{Name: "drawn", Layout: "*.\n..", MaxMines: 2}), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestTimeAttack(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+		broker: newBroker(),
+	}
+
+	Convey("Test Time Attack", t, func() {
+		Convey("Timed games end on their own when the clock runs out", func() {
+			game := models.Game{Name: "hurry", Layout: "*...\n....\n....\n...*", TimeLimit: 1}
+			So(service.NewGame(context.TODO(), &game), ShouldBeNil)
+			So(game.Deadline, ShouldNotBeNil)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			updates, err := service.Subscribe(ctx, "hurry")
+			So(err, ShouldBeNil)
+			select {
+			case update := <-updates:
+				So(update.Event.Action, ShouldEqual, models.ActionTimeout)
+				So(update.Game.Status, ShouldEqual, models.StatusTimedOut)
+			case <-time.After(3 * time.Second):
+				So("no timeout", ShouldBeEmpty)
+			}
+
+			_, err = service.Click(context.TODO(), models.ClickRequest{Name: "hurry", Row: 1, Column: 1})
+			So(err, ShouldNotBeNil)
+
+			replay, _ := service.Replay(context.TODO(), "hurry")
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Status, ShouldEqual, models.StatusTimedOut)
+		})
+		Convey("Games are lost with the last click allowed", func() {
+			game := models.Game{Name: "frugal", Layout: "*...\n....\n....\n...*", MaxClicks: 2}
+			So(service.NewGame(context.TODO(), &game), ShouldBeNil)
+
+			res, err := service.Click(context.TODO(), models.ClickRequest{Name: "frugal", Row: 0, Column: 1})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusInProgress)
+			res, err = service.Click(context.TODO(), models.ClickRequest{Name: "frugal", Row: 1, Column: 0})
+			So(err, ShouldBeNil)
+			So(res.Clicks, ShouldEqual, 2)
+			So(res.Status, ShouldEqual, models.StatusLost)
+		})
+	})
+}
+
+func TestStatus(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	service.NewGame(context.TODO(), &models.Game{Name: "done", Layout: "*..\n...\n..."})
+	service.Click(context.TODO(), models.ClickRequest{Name: "done", Row: 2, Column: 2})
+
+	Convey("Test Status", t, func() {
+		Convey("Games that are over take no more moves", func() {
+			game, err := service.LoadGame(context.TODO(), "done")
+			So(err, ShouldBeNil)
+			So(game.Status, ShouldEqual, models.StatusWon)
+
+			_, err = service.Click(context.TODO(), models.ClickRequest{Name: "done", Row: 0, Column: 0})
+			So(err, ShouldResemble, models.StatusError{Operation: models.ActionClick, Status: models.StatusWon})
+			So(err.(models.StatusError).StatusCode(), ShouldEqual, 409)
+			_, err = service.Hint(context.TODO(), "done")
+			So(err, ShouldHaveSameTypeAs, models.StatusError{})
+		})
+		Convey("Transitions", func() {
+			So(models.StatusNew.CanBecome(models.StatusLost), ShouldBeTrue)
+			So(models.StatusPaused.CanBecome(models.StatusWon), ShouldBeFalse)
+			So(models.StatusWon.CanBecome(models.StatusInProgress), ShouldBeFalse)
+			So(models.StatusLost.CanBecome(models.StatusInProgress), ShouldBeFalse)
+			So(models.StatusLost.CanUndoTo(models.StatusInProgress), ShouldBeTrue)
+		})
+	})
+}
+
+func TestPause(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	game := models.Game{Name: "break", Layout: "*...\n....\n....\n...*", TimeLimit: 60}
+	service.NewGame(context.TODO(), &game)
+	service.Click(context.TODO(), models.ClickRequest{Name: "break", Row: 0, Column: 1})
+
+	Convey("Test Pause", t, func() {
+		Convey("Paused games hide their board and take no clicks", func() {
+			res, err := service.Pause(context.TODO(), "break")
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusPaused)
+			So(res.Board, ShouldBeNil)
+
+			loaded, err := service.LoadGame(context.TODO(), "break")
+			So(err, ShouldBeNil)
+			So(loaded.Board, ShouldBeNil)
+
+			_, err = service.Click(context.TODO(), models.ClickRequest{Name: "break", Row: 1, Column: 0})
+			So(err, ShouldHaveSameTypeAs, models.StatusError{})
+			_, err = service.Pause(context.TODO(), "break")
+			So(err, ShouldNotBeNil)
+			_, err = service.Replay(context.TODO(), "break")
+			So(err, ShouldHaveSameTypeAs, models.StatusError{})
+		})
+		Convey("Resumed games put their deadline off by the pause", func() {
+			time.Sleep(50 * time.Millisecond)
+			res, err := service.Resume(context.TODO(), "break")
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusInProgress)
+			So(res.Board, ShouldNotBeNil)
+			So(res.PauseTime, ShouldBeGreaterThan, 0.05)
+			So(res.Deadline.Sub(*game.Deadline).Seconds(), ShouldEqual, res.PauseTime)
+
+			replay, _ := db.GetReplay("break")
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.PauseTime, ShouldEqual, res.PauseTime)
+			So(rebuilt.Deadline.Equal(*res.Deadline), ShouldBeTrue)
+		})
+	})
+}
+
+func TestRestart(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+	}
+
+	game := models.Game{Name: "again", Layout: "*...\n....\n....\n...*", Lives: 2}
+	service.NewGame(context.TODO(), &game)
+	service.Click(context.TODO(), models.ClickRequest{Name: "again", Row: 0, Column: 0})
+
+	Convey("Test Restart", t, func() {
+		Convey("Retries give the game up and play it again on the same board", func() {
+			res, err := service.Retry(context.TODO(), "again")
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldEqual, "again-1")
+			So(res.Origin, ShouldEqual, "again")
+			So(res.Previous, ShouldEqual, "again")
+			So(res.Attempt, ShouldEqual, 1)
+			So(res.Status, ShouldEqual, models.StatusNew)
+			So(res.Lives, ShouldEqual, 2)
+			retried := kept(db, "again-1")
+			So(retried.Board[0][0].Mine && retried.Board[3][3].Mine, ShouldBeTrue)
+			So(res.Board[0][0].Mine, ShouldBeFalse)
+			So(res.Board[0][0].Clicked, ShouldBeFalse)
+
+			original, _ := service.LoadGame(context.TODO(), "again")
+			So(original.Status, ShouldEqual, models.StatusAbandoned)
+			So(original.Next, ShouldEqual, "again-1")
+			_, err = service.Restart(context.TODO(), "again")
+			So(err, ShouldNotBeNil)
+
+			replay, _ := service.Replay(context.TODO(), "again")
+			rebuilt, err := Rebuild(replay)
+			So(err, ShouldBeNil)
+			So(rebuilt.Status, ShouldEqual, models.StatusAbandoned)
+			So(rebuilt.Next, ShouldEqual, "again-1")
+		})
+		Convey("Games played again can't be brought back", func() {
+			service.NewGame(context.TODO(), &models.Game{Name: "undone", Layout: "*...\n....", UndoLimit: 1})
+			service.Click(context.TODO(), models.ClickRequest{Name: "undone", Row: 0, Column: 0})
+			_, err := service.Retry(context.TODO(), "undone")
+			So(err, ShouldBeNil)
+			_, err = service.Undo(context.TODO(), "undone")
+			So(err, ShouldNotBeNil)
+			_, err = service.Click(context.TODO(), models.ClickRequest{Name: "undone", Row: 1, Column: 1})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Restarts play the game again on a new board", func() {
+			res, err := service.Restart(context.TODO(), "again-1")
+			So(err, ShouldBeNil)
+			So(res.Name, ShouldEqual, "again-2")
+			So(res.Origin, ShouldEqual, "again")
+			So(res.Previous, ShouldEqual, "again-1")
+			So(res.Attempt, ShouldEqual, 2)
+			So(res.Mines, ShouldEqual, 2)
+		})
+		Convey("Games whose next name is taken are left as they were", func() {
+			service.NewGame(context.TODO(), &models.Game{Name: "taken", Layout: "*...\n....\n....\n...*"})
+			service.Click(context.TODO(), models.ClickRequest{Name: "taken", Row: 0, Column: 1})
+			service.NewGame(context.TODO(), &models.Game{Name: "taken-1"})
+			_, err := service.Restart(context.TODO(), "taken")
+			So(err, ShouldNotBeNil)
+			original, _ := service.LoadGame(context.TODO(), "taken")
+			So(original.Status, ShouldEqual, models.StatusInProgress)
+			So(original.Next, ShouldBeEmpty)
+		})
+		Convey("Games given up can't be given up again", func() {
+			res, err := service.Surrender(context.TODO(), "again-2")
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, models.StatusAbandoned)
+			_, err = service.Surrender(context.TODO(), "again-2")
+			So(err, ShouldHaveSameTypeAs, models.StatusError{})
+		})
+		Convey("Games played again are deleted from the last one back", func() {
+			So(service.DeleteGame(context.TODO(), models.DeleteGameRequest{Name: "again-1"}), ShouldNotBeNil)
+			So(service.DeleteGame(context.TODO(), models.DeleteGameRequest{Name: "again-2"}), ShouldBeNil)
+			previous, _ := service.LoadGame(context.TODO(), "again-1")
+			So(previous.Next, ShouldBeEmpty)
+			So(service.DeleteGame(context.TODO(), models.DeleteGameRequest{Name: "again-1"}), ShouldBeNil)
+		})
+	})
+}
+
+func TestListGames(t *testing.T) {
+
+	var logger log.Logger
+	{
+		logger = log.NewLogfmtLogger(os.Stdout)
+		logger = level.NewFilter(logger, level.AllowAll())
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+		logger = log.With(logger, "caller", log.DefaultCaller)
+		logger = log.With(logger, "svc", "Minesweeper Test")
+	}
+	db := db.New()
+
+	service := minesweeper{
+		logger: logger,
+		db:     db,
+		broker: newBroker(),
+	}
+
+	for _, name := range []string{"list-a", "list-b", "list-c", "list-d"} {
+		service.NewGame(context.TODO(), &models.Game{Name: name, Player: "ann", Layout: "*...\n....\n....\n...*"})
+	}
+	service.NewGame(context.TODO(), &models.Game{Name: "list-e", Player: "bob"})
+	service.Click(context.TODO(), models.ClickRequest{Name: "list-b", Row: 0, Column: 1})
+
+	Convey("Test List Games", t, func() {
+		Convey("Games are picked by owner and status", func() {
+			page, err := service.ListGames(context.TODO(), models.GameQuery{Owner: "ann", Status: []models.Status{models.StatusInProgress}})
+			So(err, ShouldBeNil)
+			So(len(page.Games), ShouldEqual, 1)
+			So(page.Games[0].Name, ShouldEqual, "list-b")
+			So(page.Games[0].Board, ShouldBeNil)
+			So(page.Next, ShouldBeEmpty)
+
+			_, err = service.ListGames(context.TODO(), models.GameQuery{Status: []models.Status{"start"}})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Pages follow each other through their cursor", func() {
+			query := models.GameQuery{Owner: "ann", Sort: "-name", Limit: 3}
+			page, err := service.ListGames(context.TODO(), query)
+			So(err, ShouldBeNil)
+			So(len(page.Games), ShouldEqual, 3)
+			So(page.Games[0].Name, ShouldEqual, "list-d")
+			So(page.Next, ShouldNotBeEmpty)
+
+			query.Cursor = page.Next
+			page, err = service.ListGames(context.TODO(), query)
+			So(err, ShouldBeNil)
+			So(len(page.Games), ShouldEqual, 1)
+			So(page.Games[0].Name, ShouldEqual, "list-a")
+			So(page.Next, ShouldBeEmpty)
+		})
+		Convey("Games are picked by when they were created", func() {
+			last, _ := service.LoadGame(context.TODO(), "list-e")
+			page, err := service.ListGames(context.TODO(), models.GameQuery{From: &last.Created})
+			So(err, ShouldBeNil)
+			So(len(page.Games), ShouldEqual, 1)
+			So(page.Games[0].Name, ShouldEqual, "list-e")
+		})
+		Convey("Deleted games are gone, replay and watchers included", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			updates, err := service.Subscribe(ctx, "list-e")
+			So(err, ShouldBeNil)
+			So(service.DeleteGame(context.TODO(), models.DeleteGameRequest{Name: "list-e", Player: "ann"}), ShouldNotBeNil)
+			So(service.DeleteGame(context.TODO(), models.DeleteGameRequest{Name: "list-e", Player: "bob"}), ShouldBeNil)
+			select {
+			case _, open := <-updates:
+				So(open, ShouldBeFalse)
+			case <-time.After(time.Second):
+				So("updates still open", ShouldBeEmpty)
+			}
+			_, err = service.LoadGame(context.TODO(), "list-e")
+			So(err, ShouldNotBeNil)
+			_, err = service.Replay(context.TODO(), "list-e")
+			So(err, ShouldNotBeNil)
+			So(service.DeleteGame(context.TODO(), models.DeleteGameRequest{Name: "list-e", Player: "bob"}), ShouldNotBeNil)
+		})
+	})
+}
+
+//kept returns a game as the service keeps it, with the mines its players don't get to see while it is being played
+func kept(storage db.MineDBManager, name string) models.Game {
+
+	game, _ := storage.GetGame(name)
+	return *game
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/minesweeper/pkg/models"
+)
+
+//operations are the statuses a game has to be in to take each operation. Any other status is answered with a StatusError.
+//Undos take back fatal clicks too, and a timeout can only happen while the clock runs, which it doesn't in paused games
+var operations = map[string][]models.Status{
+	models.ActionClick:     {models.StatusNew, models.StatusInProgress},
+	models.ActionFlag:      {models.StatusNew, models.StatusInProgress},
+	models.ActionChord:     {models.StatusInProgress},
+	models.ActionHint:      {models.StatusNew, models.StatusInProgress},
+	models.ActionJoin:      {models.StatusNew, models.StatusInProgress},
+	models.ActionUndo:      {models.StatusInProgress, models.StatusLost},
+	models.ActionTimeout:   {models.StatusNew, models.StatusInProgress},
+	models.ActionPause:     {models.StatusInProgress},
+	models.ActionResume:    {models.StatusPaused},
+	models.ActionSurrender: {models.StatusNew, models.StatusInProgress, models.StatusPaused},
+}
+
+//allow checks that a game can take an operation in its status. Games played again take none, since undos would bring them
+//back alongside the game that replaced them
+func allow(game *models.Game, operation string) error {
+
+	if game.Next != "" {
+		return fmt.Errorf("Game was played again as %s", game.Next)
+	}
+	for _, status := range operations[operation] {
+		if game.Status == status {
+			return nil
+		}
+	}
+	return models.StatusError{Operation: operation, Status: game.Status}
+}
+
+//transition moves a game to another status, if its transitions allow it
+func transition(game *models.Game, to models.Status) error {
+
+	if !game.Status.CanBecome(to) {
+		return models.StatusError{To: to, Status: game.Status}
+	}
+	game.Status = to
+	return nil
+}
+
+//over tells whether a game has ended, whatever the outcome
+func over(game *models.Game) bool {
+	return game.Status.Over()
+}
+
+//lost tells whether a game ended without a win: a mine hit with the last life, the last click spent, the clock run out or given up
+func lost(game *models.Game) bool {
+	return game.Status.Over() && game.Status != models.StatusWon
+}
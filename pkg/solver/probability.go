@@ -0,0 +1,366 @@
+package solver
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	maxNodes    = 1 << 18 //Steps the exact enumeration of a group of cells can take before falling back to sampling
+	samples     = 200     //Solutions drawn when sampling a group
+	sampleNodes = 5000    //Steps each sample can take to find its solution
+)
+
+//Probability is the chance of a hidden cell holding a mine
+type Probability struct {
+	Cell
+	Mine float64 `json:"mine"`
+}
+
+//group is a set of frontier cells tied together by numbers. Groups don't share any number,
+//so each of them can be enumerated on its own and only the amount of mines they hold has to be combined
+type group struct {
+	cells       []Cell
+	constraints []constraint
+	of          [][]int //constraints each cell of the group is part of
+
+	//results of the enumeration, by how many mines a solution places in the group. They are shares of all the solutions
+	//found, not counts, so combining many groups doesn't overflow
+	solutions map[int]float64
+	mines     map[int][]float64 //share of the solutions with a mine in each cell
+
+	placed     []int //mines placed so far in each constraint
+	open       []int //cells of each constraint still unassigned
+	assignment []bool
+	maxMines   int
+	nodes      int
+	budget     int
+	rnd        *rand.Rand //when set, values are tried in random order and the walk stops at the first solution
+	done       bool       //set once a sample found its solution
+}
+
+//Probabilities returns the chance of every hidden cell holding a mine, given what is visible.
+//Every placement of mines in the frontier (the hidden cells next to numbers) that agrees with the numbers is counted,
+//weighted by the ways the remaining mines fit in the rest of the board. The frontier is split in groups of cells
+//that share numbers, and when a group is too big to enumerate its placements are sampled instead, so the result
+//is an approximation; exact tells which one it was. When not even a sample can be found, the cells of the group
+//take the share of mines of the numbers around them. Nil is returned if no placement agrees with the numbers
+func Probabilities(b *Board) (probabilities []Probability, exact bool) {
+
+	unknown, left := b.unknown(b.hit())
+	groups := b.groups()
+
+	exact = true
+	frontier := 0
+	for _, g := range groups {
+		frontier += len(g.cells)
+		g.maxMines = left
+		enumerated := g.enumerate()
+		if !enumerated {
+			exact = false
+		}
+		if len(g.solutions) == 0 {
+			//a group enumerated to the end without a solution proves the numbers wrong, whatever the other groups did
+			if enumerated {
+				return nil, true
+			}
+			g.estimate()
+		}
+	}
+	others := len(unknown) - frontier
+
+	//ways[M] is the share of placements of the whole frontier with M mines,
+	//and rest[g][M] the same leaving group g out
+	ways := combine(groups, -1)
+	weight := func(m int) float64 {
+		if left-m < 0 || left-m > others {
+			return math.Inf(-1)
+		}
+		return logBinomial(others, left-m)
+	}
+	//weights are kept relative to the biggest one, since the number of ways to place the remaining mines easily overflows
+	maxLog := math.Inf(-1)
+	for m := range ways {
+		if w := weight(m); w > maxLog {
+			maxLog = w
+		}
+	}
+	//no amount of mines in the frontier leaves a number of them that fits in the rest of the board
+	if math.IsInf(maxLog, -1) {
+		return nil, exact
+	}
+	var total, otherMines float64
+	for m, share := range ways {
+		w := math.Exp(weight(m)-maxLog) * share
+		total += w
+		if others > 0 {
+			otherMines += w * float64(left-m) / float64(others)
+		}
+	}
+	if total == 0 {
+		return nil, exact
+	}
+
+	mine := make(map[Cell]float64)
+	for i, g := range groups {
+		rest := combine(groups, i)
+		for m, counts := range g.mines {
+			for r, share := range rest {
+				w := math.Exp(weight(m+r)-maxLog) * share
+				for j, n := range counts {
+					mine[g.cells[j]] += w * n
+				}
+			}
+		}
+	}
+
+	for _, cell := range unknown {
+		p, ok := mine[cell]
+		if !ok {
+			p = otherMines
+		}
+		//rounding can take the share of the cells that are certainly mines a hair over 1
+		probabilities = append(probabilities, Probability{Cell: cell, Mine: math.Min(p/total, 1)})
+	}
+	return probabilities, exact
+}
+
+//groups splits the frontier in groups of cells that share numbers, with their cells in the order they were reached,
+//so the numbers of a group are closed early in its enumeration
+func (b *Board) groups() []*group {
+
+	constraints := b.constraints(b.hit())
+	byCell := make(map[Cell][]int)
+	for k, c := range constraints {
+		for _, cell := range c.cells {
+			byCell[cell] = append(byCell[cell], k)
+		}
+	}
+
+	var groups []*group
+	seenConstraint := make([]bool, len(constraints))
+	seenCell := make(map[Cell]bool)
+	for k := range constraints {
+		if seenConstraint[k] {
+			continue
+		}
+		g := &group{}
+		index := make(map[Cell]int)
+		queue := []int{k}
+		seenConstraint[k] = true
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			g.constraints = append(g.constraints, constraints[c])
+			for _, cell := range constraints[c].cells {
+				if seenCell[cell] {
+					continue
+				}
+				seenCell[cell] = true
+				index[cell] = len(g.cells)
+				g.cells = append(g.cells, cell)
+				for _, next := range byCell[cell] {
+					if !seenConstraint[next] {
+						seenConstraint[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+		g.of = make([][]int, len(g.cells))
+		for gk, c := range g.constraints {
+			for _, cell := range c.cells {
+				g.of[index[cell]] = append(g.of[index[cell]], gk)
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+//enumerate finds the placements of mines in the group that agree with its numbers. It returns false if it had to sample them
+func (g *group) enumerate() bool {
+
+	g.reset()
+	g.budget = maxNodes
+	if g.walk(0, 0) {
+		g.normalize()
+		return true
+	}
+
+	//a fixed seed keeps the hints of a board the same every time
+	g.reset()
+	g.budget = sampleNodes
+	g.rnd = rand.New(rand.NewSource(1))
+	for s := 0; s < samples; s++ {
+		g.nodes, g.done = 0, false
+		g.walk(0, 0)
+	}
+	g.normalize()
+	return false
+}
+
+func (g *group) reset() {
+
+	g.placed = make([]int, len(g.constraints))
+	g.open = make([]int, len(g.constraints))
+	for k, c := range g.constraints {
+		g.open[k] = len(c.cells)
+	}
+	g.assignment = make([]bool, len(g.cells))
+	g.solutions = make(map[int]float64)
+	g.mines = make(map[int][]float64)
+	g.nodes = 0
+}
+
+//walk assigns the cells from i on, given the mines placed so far. It returns false when it runs out of steps
+func (g *group) walk(i, mines int) bool {
+
+	g.nodes++
+	if g.nodes > g.budget {
+		return false
+	}
+	if i == len(g.cells) {
+		g.record(mines)
+		g.done = g.rnd != nil
+		return true
+	}
+
+	values := []bool{false, true}
+	if g.rnd != nil && g.rnd.Intn(2) == 0 {
+		values[0], values[1] = true, false
+	}
+	for _, mine := range values {
+		if mine && mines == g.maxMines {
+			continue
+		}
+		ok := g.assign(i, mine)
+		if ok {
+			ok = g.walk(i+1, mines+boolToInt(mine))
+			if !ok {
+				g.unassign(i, mine)
+				return false
+			}
+		}
+		g.unassign(i, mine)
+		//a sample is a single solution
+		if g.done {
+			return true
+		}
+	}
+	return true
+}
+
+//assign sets the value of a cell, and tells if every number it is next to can still be met
+func (g *group) assign(i int, mine bool) bool {
+
+	g.assignment[i] = mine
+	ok := true
+	for _, k := range g.of[i] {
+		g.open[k]--
+		if mine {
+			g.placed[k]++
+		}
+		need := g.constraints[k].mines
+		if g.placed[k] > need || g.placed[k]+g.open[k] < need {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (g *group) unassign(i int, mine bool) {
+
+	for _, k := range g.of[i] {
+		g.open[k]++
+		if mine {
+			g.placed[k]--
+		}
+	}
+}
+
+func (g *group) record(mines int) {
+
+	g.solutions[mines]++
+	counts, ok := g.mines[mines]
+	if !ok {
+		counts = make([]float64, len(g.cells))
+		g.mines[mines] = counts
+	}
+	for i, mine := range g.assignment {
+		if mine {
+			counts[i]++
+		}
+	}
+}
+
+//estimate gives each cell of the group the average share of mines of the numbers around it,
+//taken as the only way the group can be, for when its placements can't be enumerated nor sampled
+func (g *group) estimate() {
+
+	share := make([]float64, len(g.cells))
+	var mines float64
+	for i, of := range g.of {
+		for _, k := range of {
+			share[i] += float64(g.constraints[k].mines) / float64(len(g.constraints[k].cells))
+		}
+		share[i] /= float64(len(of))
+		mines += share[i]
+	}
+	m := int(math.Round(mines))
+	if m > g.maxMines {
+		m = g.maxMines
+	}
+	g.solutions = map[int]float64{m: 1}
+	g.mines = map[int][]float64{m: share}
+}
+
+//normalize turns the counts of solutions into shares of the total
+func (g *group) normalize() {
+
+	var total float64
+	for _, n := range g.solutions {
+		total += n
+	}
+	for m := range g.solutions {
+		g.solutions[m] /= total
+		for i := range g.mines[m] {
+			g.mines[m][i] /= total
+		}
+	}
+}
+
+//combine returns, for every amount of mines, the share of placements of all the groups but skip that hold that many mines
+func combine(groups []*group, skip int) map[int]float64 {
+
+	ways := map[int]float64{0: 1}
+	for i, g := range groups {
+		if i == skip {
+			continue
+		}
+		next := make(map[int]float64)
+		for a, wa := range ways {
+			for b, wb := range g.solutions {
+				next[a+b] += wa * wb
+			}
+		}
+		ways = next
+	}
+	return ways
+}
+
+//logBinomial is the logarithm of n choose k
+func logBinomial(n, k int) float64 {
+
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,260 @@
+//Package solver finds the moves a player can deduce from what is visible of a board:
+//the numbers of the clicked cells and how many mines the board has. It never looks at where the mines are
+package solver
+
+import (
+	"fmt"
+
+	"github.com/minesweeper/pkg/models"
+	"github.com/minesweeper/pkg/topology"
+)
+
+//Cell is a position in the board
+type Cell struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+//Deduction is a cell whose content is certain, along with why
+type Deduction struct {
+	Cell
+	Mine   bool   `json:"mine"`   //Whether the cell holds a mine or is safe to click
+	Reason string `json:"reason"` //Explanation of how the deduction was made
+}
+
+//Board is what the player can see of a game
+type Board struct {
+	Rows     int
+	Columns  int
+	Mines    int
+	Revealed [][]bool
+	Numbers  [][]int
+	Hit      [][]bool          //Mines the player already hit, which games with lives reveal
+	Topology topology.Topology //Which cells neighbour each other. The plane when nil
+}
+
+//FromGame takes the visible part of a game. Mines are left out, except for the count and the ones already hit.
+//Cube boards are taken as the rows of every layer, one layer after another, as the game holds them
+func FromGame(game *models.Game) *Board {
+
+	rows := len(game.Board)
+	b := &Board{
+		Rows:     rows,
+		Columns:  game.Columns,
+		Mines:    game.Mines,
+		Revealed: make([][]bool, rows),
+		Numbers:  make([][]int, rows),
+		Hit:      make([][]bool, rows),
+		Topology: topology.For(game.Topology, game.Layers, game.Offsets),
+	}
+	for i, row := range game.Board {
+		b.Revealed[i] = make([]bool, game.Columns)
+		b.Numbers[i] = make([]int, game.Columns)
+		b.Hit[i] = make([]bool, game.Columns)
+		for j, cell := range row {
+			switch {
+			case cell.Clicked && cell.Mine:
+				b.Hit[i][j] = true
+			case cell.Clicked:
+				b.Revealed[i][j] = true
+				b.Numbers[i][j] = cell.Number
+			}
+		}
+	}
+	return b
+}
+
+//hit returns the mines already hit, which are known from the start
+func (b *Board) hit() map[Cell]bool {
+
+	known := make(map[Cell]bool)
+	for i, row := range b.Hit {
+		for j, mine := range row {
+			if mine {
+				known[Cell{i, j}] = true
+			}
+		}
+	}
+	return known
+}
+
+//neighbours returns the cells surrounding a cell, as the topology of the board says
+func (b *Board) neighbours(c Cell) []Cell {
+
+	t := b.Topology
+	if t == nil {
+		t = topology.Of(topology.Plane)
+	}
+	var res []Cell
+	for _, n := range t.Neighbours(b.Rows, b.Columns, topology.Cell(c)) {
+		res = append(res, Cell(n))
+	}
+	return res
+}
+
+//constraint says that exactly Mines of the Cells hold a mine
+type constraint struct {
+	cells  []Cell
+	mines  int
+	origin string //how the constraint came to be, for the reasons of deductions
+}
+
+//Solve returns every cell whose content follows with certainty from the board.
+//Each number says how many of its hidden neighbours are mines; when a number is already satisfied its other neighbours are safe,
+//and when it has as many hidden neighbours as mines all of them are mines. Comparing pairs of numbers,
+//when the hidden neighbours of one are a subset of the other's, the remaining cells hold the difference
+func Solve(b *Board) []Deduction {
+
+	known := b.hit() //cell -> is a mine
+	var found []Deduction
+
+	for changed := true; changed; {
+		changed = false
+		constraints := b.constraints(known)
+
+		add := func(cells []Cell, mine bool, reason string) {
+			for _, c := range cells {
+				if _, ok := known[c]; ok {
+					continue
+				}
+				known[c] = mine
+				found = append(found, Deduction{Cell: c, Mine: mine, Reason: reason})
+				changed = true
+			}
+		}
+
+		for _, k := range constraints {
+			switch {
+			case k.mines == 0:
+				add(k.cells, false, fmt.Sprintf("%s already has all its mines around it", k.origin))
+			case k.mines == len(k.cells):
+				add(k.cells, true, fmt.Sprintf("%s has as many hidden cells around it as mines left", k.origin))
+			}
+		}
+		if changed {
+			continue
+		}
+
+		for _, a := range constraints {
+			for _, c := range constraints {
+				if len(a.cells) >= len(c.cells) || !subset(a.cells, c.cells) {
+					continue
+				}
+				rest := difference(c.cells, a.cells)
+				mines := c.mines - a.mines
+				switch {
+				case mines == 0:
+					add(rest, false, fmt.Sprintf("%s needs %d mines, all of them next to %s", c.origin, c.mines, a.origin))
+				case mines == len(rest):
+					add(rest, true, fmt.Sprintf("%s needs %d more mines than %s can hold", c.origin, mines, a.origin))
+				}
+			}
+		}
+		if changed {
+			continue
+		}
+
+		//the last resort is the count of mines of the whole board
+		unknown, left := b.unknown(known)
+		switch {
+		case len(unknown) > 0 && left == 0:
+			add(unknown, false, "every mine of the board has been found")
+		case len(unknown) > 0 && left == len(unknown):
+			add(unknown, true, "there are as many hidden cells left as mines")
+		}
+	}
+	return found
+}
+
+//unknown returns the hidden cells whose content isn't known yet, and how many mines are left among them
+func (b *Board) unknown(known map[Cell]bool) ([]Cell, int) {
+
+	var cells []Cell
+	left := b.Mines
+	for i := 0; i < b.Rows; i++ {
+		for j := 0; j < b.Columns; j++ {
+			mine, ok := known[Cell{i, j}]
+			switch {
+			case ok && mine:
+				left--
+			case !ok && !b.Revealed[i][j]:
+				cells = append(cells, Cell{i, j})
+			}
+		}
+	}
+	return cells, left
+}
+
+//constraints builds a constraint from every number with hidden neighbours, leaving out the cells already known
+func (b *Board) constraints(known map[Cell]bool) []constraint {
+
+	var res []constraint
+	for i := 0; i < b.Rows; i++ {
+		for j := 0; j < b.Columns; j++ {
+			if !b.Revealed[i][j] {
+				continue
+			}
+			k := constraint{mines: b.Numbers[i][j], origin: fmt.Sprintf("the %d at (%d, %d)", b.Numbers[i][j], i, j)}
+			for _, n := range b.neighbours(Cell{i, j}) {
+				if b.Revealed[n.Row][n.Column] {
+					continue
+				}
+				if mine, ok := known[n]; ok {
+					if mine {
+						k.mines--
+					}
+					continue
+				}
+				k.cells = append(k.cells, n)
+			}
+			if len(k.cells) > 0 {
+				res = append(res, k)
+			}
+		}
+	}
+	return res
+}
+
+//Guess returns the hidden cell least likely to hold a mine, and that likelihood, for when nothing is certain
+func Guess(b *Board) (Cell, float64) {
+
+	best, bestP := Cell{-1, -1}, 2.0
+	probabilities, _ := Probabilities(b)
+	for _, p := range probabilities {
+		if p.Mine < bestP {
+			best, bestP = p.Cell, p.Mine
+		}
+	}
+	return best, bestP
+}
+
+//subset tells if every cell of a is in b
+func subset(a, b []Cell) bool {
+
+	in := make(map[Cell]bool, len(b))
+	for _, c := range b {
+		in[c] = true
+	}
+	for _, c := range a {
+		if !in[c] {
+			return false
+		}
+	}
+	return true
+}
+
+//difference returns the cells of a that aren't in b
+func difference(a, b []Cell) []Cell {
+
+	in := make(map[Cell]bool, len(b))
+	for _, c := range b {
+		in[c] = true
+	}
+	var res []Cell
+	for _, c := range a {
+		if !in[c] {
+			res = append(res, c)
+		}
+	}
+	return res
+}
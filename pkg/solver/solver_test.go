@@ -0,0 +1,111 @@
+package solver
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+//board builds a board from rows where digits are revealed numbers and '#' hidden cells
+func board(mines int, rows ...string) *Board {
+
+	b := &Board{Rows: len(rows), Columns: len(rows[0]), Mines: mines}
+	for _, row := range rows {
+		revealed := make([]bool, len(row))
+		numbers := make([]int, len(row))
+		for j, c := range row {
+			if c != '#' {
+				revealed[j] = true
+				numbers[j] = int(c - '0')
+			}
+		}
+		b.Revealed = append(b.Revealed, revealed)
+		b.Numbers = append(b.Numbers, numbers)
+	}
+	return b
+}
+
+//outcome maps each deduced cell to whether it is a mine
+func outcome(deductions []Deduction) map[Cell]bool {
+
+	res := make(map[Cell]bool)
+	for _, d := range deductions {
+		res[d.Cell] = d.Mine
+	}
+	return res
+}
+
+func TestSolve(t *testing.T) {
+
+	Convey("Test Solve", t, func() {
+		Convey("Single mine", func() {
+			res := outcome(Solve(board(1, "#1", "11")))
+			So(res, ShouldResemble, map[Cell]bool{{0, 0}: true})
+		})
+		Convey("One two one", func() {
+			res := outcome(Solve(board(2, "###", "121")))
+			So(res, ShouldResemble, map[Cell]bool{{0, 0}: true, {0, 1}: false, {0, 2}: true})
+		})
+		Convey("Subset", func() {
+			res := outcome(Solve(board(3, "####", "1100")))
+			So(res[Cell{0, 2}], ShouldBeFalse)
+			So(res[Cell{0, 3}], ShouldBeFalse)
+		})
+		Convey("Mine count", func() {
+			res := outcome(Solve(board(0, "##", "00")))
+			So(res, ShouldResemble, map[Cell]bool{{0, 0}: false, {0, 1}: false})
+		})
+		Convey("Reasons", func() {
+			for _, d := range Solve(board(2, "###", "121")) {
+				So(d.Reason, ShouldNotBeEmpty)
+			}
+		})
+	})
+
+}
+
+func TestGuess(t *testing.T) {
+
+	Convey("Test Guess", t, func() {
+		Convey("Avoids crowded numbers", func() {
+			b := board(4, "####", "#3##", "####", "####", "####")
+			cell, p := Guess(b)
+			So(p, ShouldAlmostEqual, 1.0/11)
+			So(cell.Row > 2 || cell.Column > 2, ShouldBeTrue)
+		})
+	})
+
+}
+
+func TestProbabilities(t *testing.T) {
+
+	Convey("Test Probabilities", t, func() {
+		Convey("Certain cells", func() {
+			probabilities, exact := Probabilities(board(2, "###", "121"))
+			So(exact, ShouldBeTrue)
+			So(probabilities, ShouldResemble, []Probability{{Cell{0, 0}, 1}, {Cell{0, 1}, 0}, {Cell{0, 2}, 1}})
+		})
+		Convey("Even chances", func() {
+			probabilities, _ := Probabilities(board(1, "##", "1#"))
+			for _, p := range probabilities {
+				So(p.Mine, ShouldAlmostEqual, 1.0/3)
+			}
+		})
+		Convey("Cells away from numbers", func() {
+			//the 1s take one of the two mines among their 4 hidden neighbours, so the other is somewhere in the 6 cells of the right
+			probabilities, _ := Probabilities(board(2, "#1####", "#1####"))
+			for _, p := range probabilities {
+				if p.Column < 3 {
+					So(p.Mine, ShouldAlmostEqual, 1.0/4)
+				} else {
+					So(p.Mine, ShouldAlmostEqual, 1.0/6)
+				}
+			}
+		})
+		Convey("More mines than the board holds", func() {
+			probabilities, _ := Probabilities(board(3, "1#"))
+			So(probabilities, ShouldBeNil)
+		})
+	})
+
+}
@@ -0,0 +1,266 @@
+//Package topology says which cells of a board neighbour each other. Numbers, the reveal of empty areas, chords and the solver
+//all go through it, so a board behaves the same everywhere whatever its shape.
+//
+//Every board is stored as rows and columns. Hexagonal boards use offset coordinates ("odd-r"): rows of pointy-top hexagons,
+//with odd rows shifted half a cell to the right, so cells keep being clicked by their row and column.
+//Cubic boards keep the rows of every layer one layer after another, so their topology needs to know how many layers there are.
+//
+//Square boards can also change which cells are the neighbours of a cell, with a list of the row and column offsets of those cells
+package topology
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	Plane = "plane" //Plane is the classic board, whose edges are walls
+	Torus = "torus" //Torus is a board whose edges wrap around: the top row neighbours the bottom one, and the left column the right one
+	Hex   = "hex"   //Hex is a board of hexagons, each with six neighbours
+	Cube  = "cube"  //Cube is a board of several layers, where each cell neighbours the 26 cells around it in three dimensions
+)
+
+const (
+	Knight   = "knight"   //Knight neighbours the cells a chess knight reaches from a cell
+	Cross    = "cross"    //Cross neighbours the four cells that share a side with a cell
+	Extended = "extended" //Extended neighbours the 24 cells up to two cells away from a cell
+	Custom   = "custom"   //Custom neighbours the cells at the offsets given by the game
+
+	MaxReach = 3 //How many rows or columns away a neighbour can be
+)
+
+//Cell is a position in a board. Cells are also used as offsets, the rows and columns from one cell to another
+type Cell struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+//Geometry tells clients how to draw the cells of a board
+type Geometry struct {
+	Cell       string `json:"cell"`             //square or hexagon
+	Offset     string `json:"offset,omitempty"` //How rows of hexagons are placed. odd-r: pointy-top hexagons, odd rows shifted half a cell to the right
+	Neighbours int    `json:"neighbours"`       //Most neighbours a cell can have
+	Wrap       bool   `json:"wrap"`             //Whether the edges of the board wrap around
+	Layers     int    `json:"layers,omitempty"` //Layers of cubic boards
+}
+
+//Topology is the shape of a board
+type Topology interface {
+	//Neighbours returns the cells next to a cell of a board of the given size, each of them once and never the cell itself
+	Neighbours(rows int, columns int, cell Cell) []Cell
+	//Geometry returns how the cells of the board are drawn
+	Geometry() Geometry
+}
+
+var topologies = map[string]Topology{
+	"":    plane{},
+	Plane: plane{},
+	Torus: torus{},
+	Hex:   hex{},
+	Cube:  cube{layers: 1},
+}
+
+//Get returns a topology by its name. The empty name is the plane
+func Get(name string) (Topology, error) {
+
+	t, ok := topologies[name]
+	if !ok {
+		return nil, errors.New("unknown topology")
+	}
+	return t, nil
+}
+
+//Of returns a topology by its name, the plane for unknown names. Games check their topology when created, so it's always known after that
+func Of(name string) Topology {
+
+	if t, ok := topologies[name]; ok {
+		return t
+	}
+	return plane{}
+}
+
+//For returns the topology of a board by its name, layers and the offsets of its neighbours. Only cubic boards have more than one layer,
+//and only square boards, plane or torus, have their own offsets. Other boards ignore them
+func For(name string, layers int, offsets []Cell) Topology {
+
+	if name == Cube && layers > 1 {
+		return cube{layers: layers}
+	}
+	if len(offsets) > 0 && Of(name).Geometry().Cell == "square" {
+		return rule{wrap: name == Torus, offsets: offsets}
+	}
+	return Of(name)
+}
+
+var neighbourhoods = map[string][]Cell{
+	Knight: {{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}},
+	Cross:  {{-1, 0}, {0, -1}, {0, 1}, {1, 0}},
+	Extended: {
+		{-2, -2}, {-2, -1}, {-2, 0}, {-2, 1}, {-2, 2},
+		{-1, -2}, {-1, -1}, {-1, 0}, {-1, 1}, {-1, 2},
+		{0, -2}, {0, -1}, {0, 1}, {0, 2},
+		{1, -2}, {1, -1}, {1, 0}, {1, 1}, {1, 2},
+		{2, -2}, {2, -1}, {2, 0}, {2, 1}, {2, 2},
+	},
+}
+
+//Neighbourhood returns the offsets of the neighbours of a cell in a known neighbourhood
+func Neighbourhood(name string) ([]Cell, error) {
+
+	offsets, ok := neighbourhoods[name]
+	if !ok {
+		return nil, errors.New("unknown neighbourhood")
+	}
+	return append([]Cell(nil), offsets...), nil
+}
+
+//CheckOffsets checks the offsets of a custom neighbourhood: there's at least one, none of them is the cell itself or repeated,
+//and none is further than MaxReach rows or columns away
+func CheckOffsets(offsets []Cell) error {
+
+	if len(offsets) == 0 {
+		return errors.New("neighbourhood has no offsets")
+	}
+	seen := make(map[Cell]bool)
+	for _, o := range offsets {
+		if o.Row == 0 && o.Column == 0 {
+			return errors.New("a cell can't neighbour itself")
+		}
+		if o.Row < -MaxReach || o.Row > MaxReach || o.Column < -MaxReach || o.Column > MaxReach {
+			return fmt.Errorf("neighbours are at most %d cells away", MaxReach)
+		}
+		if seen[o] {
+			return errors.New("repeated offset")
+		}
+		seen[o] = true
+	}
+	return nil
+}
+
+//plane neighbours the eight surrounding cells that fall inside the board
+type plane struct{}
+
+func (plane) Neighbours(rows int, columns int, cell Cell) []Cell {
+
+	var res []Cell
+	for x := cell.Row - 1; x < cell.Row+2; x++ {
+		for y := cell.Column - 1; y < cell.Column+2; y++ {
+			if x >= 0 && x < rows && y >= 0 && y < columns && !(x == cell.Row && y == cell.Column) {
+				res = append(res, Cell{x, y})
+			}
+		}
+	}
+	return res
+}
+
+func (plane) Geometry() Geometry {
+	return Geometry{Cell: "square", Neighbours: 8}
+}
+
+//torus neighbours the eight surrounding cells, wrapping around the edges. On boards less than three cells wide
+//some of them are the same cell, or the cell itself, so they are only counted once
+type torus struct{}
+
+func (torus) Neighbours(rows int, columns int, cell Cell) []Cell {
+
+	var res []Cell
+	seen := map[Cell]bool{cell: true}
+	for dx := -1; dx < 2; dx++ {
+		for dy := -1; dy < 2; dy++ {
+			c := Cell{(cell.Row + dx + rows) % rows, (cell.Column + dy + columns) % columns}
+			if !seen[c] {
+				seen[c] = true
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+func (torus) Geometry() Geometry {
+	return Geometry{Cell: "square", Neighbours: 8, Wrap: true}
+}
+
+//hexOffsets are the row and column offsets of the six neighbours of a hexagon, for even and odd rows.
+//Odd rows are shifted right, so the rows above and below a cell reach one column further right on odd rows
+var hexOffsets = [2][6][2]int{
+	{{-1, -1}, {-1, 0}, {0, -1}, {0, 1}, {1, -1}, {1, 0}},
+	{{-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, 0}, {1, 1}},
+}
+
+//hex neighbours the six hexagons around a cell that fall inside the board
+type hex struct{}
+
+func (hex) Neighbours(rows int, columns int, cell Cell) []Cell {
+
+	var res []Cell
+	for _, o := range hexOffsets[cell.Row&1] {
+		x, y := cell.Row+o[0], cell.Column+o[1]
+		if x >= 0 && x < rows && y >= 0 && y < columns {
+			res = append(res, Cell{x, y})
+		}
+	}
+	return res
+}
+
+func (hex) Geometry() Geometry {
+	return Geometry{Cell: "hexagon", Offset: "odd-r", Neighbours: 6}
+}
+
+//cube neighbours the 26 cells around a cell in three dimensions, within the board. Rows are those of all the layers,
+//so row r of layer l is the row l*rows/layers+r
+type cube struct {
+	layers int
+}
+
+func (c cube) Neighbours(rows int, columns int, cell Cell) []Cell {
+
+	per := rows / c.layers
+	layer, row := cell.Row/per, cell.Row%per
+	var res []Cell
+	for l := layer - 1; l < layer+2; l++ {
+		for x := row - 1; x < row+2; x++ {
+			for y := cell.Column - 1; y < cell.Column+2; y++ {
+				if l < 0 || l >= c.layers || x < 0 || x >= per || y < 0 || y >= columns || (l == layer && x == row && y == cell.Column) {
+					continue
+				}
+				res = append(res, Cell{l*per + x, y})
+			}
+		}
+	}
+	return res
+}
+
+func (c cube) Geometry() Geometry {
+	return Geometry{Cell: "cube", Neighbours: 26, Layers: c.layers}
+}
+
+//rule neighbours the cells at the given offsets of a cell, on square boards. Offsets don't need to go both ways:
+//a cell can neighbour another that doesn't neighbour it back. On a torus they wrap around the edges, counting each cell once
+type rule struct {
+	wrap    bool
+	offsets []Cell
+}
+
+func (r rule) Neighbours(rows int, columns int, cell Cell) []Cell {
+
+	var res []Cell
+	seen := map[Cell]bool{cell: true}
+	for _, o := range r.offsets {
+		c := Cell{cell.Row + o.Row, cell.Column + o.Column}
+		if r.wrap {
+			c = Cell{((c.Row % rows) + rows) % rows, ((c.Column % columns) + columns) % columns}
+		} else if c.Row < 0 || c.Row >= rows || c.Column < 0 || c.Column >= columns {
+			continue
+		}
+		if !seen[c] {
+			seen[c] = true
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func (r rule) Geometry() Geometry {
+	return Geometry{Cell: "square", Neighbours: len(r.offsets), Wrap: r.wrap}
+}